@@ -0,0 +1,222 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isArchiveTarget reports whether dinder was pointed at an archive it can
+// review as a virtual tree instead of at a directory.
+func isArchiveTarget(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".zip")
+}
+
+// scanArchive lists every file inside a zip archive as a FileItem. Paths are
+// the entry names, so they are only meaningful together with the archive.
+func scanArchive(path string, cfg Config) ([]FileItem, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var items []FileItem
+	for _, entry := range reader.File {
+		if strings.HasSuffix(entry.Name, "/") {
+			continue
+		}
+
+		info := entry.FileInfo()
+		item := FileItem{
+			Path:         entry.Name,
+			Name:         info.Name(),
+			Size:         info.Size(),
+			ModTime:      entry.Modified,
+			Mode:         info.Mode(),
+			PreviewLines: cfg.PreviewLines,
+		}
+		if !matchesFilters(item, cfg) {
+			continue
+		}
+		if isCodeFile(entry.Name) {
+			item.Language = languageName(entry.Name)
+		}
+
+		if !cfg.NoPreview && isTextFile(entry.Name) && info.Size() < cfg.PreviewMaxSize && !skipsPreview(entry.Name, cfg.NoPreviewExts) {
+			if rc, err := entry.Open(); err == nil {
+				item.Preview, item.TotalLines, item.MoreLines = readPreview(rc, entry.Name, cfg.PreviewLines)
+				rc.Close()
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// rewriteArchive replaces the archive with a copy that omits the removed
+// entries. Entries are copied without recompressing them, and the original
+// is only replaced once the new archive has been written completely.
+func rewriteArchive(path string, remove map[string]bool) error {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".dinder-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	writer := zip.NewWriter(tmp)
+	writer.SetComment(reader.Comment)
+	for _, entry := range reader.File {
+		if remove[entry.Name] {
+			continue
+		}
+		if err := copyZipEntry(writer, entry); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		os.Chmod(tmp.Name(), info.Mode().Perm())
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func copyZipEntry(writer *zip.Writer, entry *zip.File) error {
+	raw, err := entry.OpenRaw()
+	if err != nil {
+		return err
+	}
+	dst, err := writer.CreateRaw(&entry.FileHeader)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, raw)
+	return err
+}
+
+// archivePreviewEntries is how many top-level entries an archive preview
+// lists.
+const archivePreviewEntries = 10
+
+// isListableArchive reports whether getArchivePreview can look inside path.
+func isListableArchive(path string) bool {
+	name := strings.ToLower(path)
+	for _, suffix := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// getArchivePreview lists the top-level entries of a zip or tar archive,
+// folders with the number of files in them, and the total entry count.
+func getArchivePreview(path string) string {
+	names, err := archiveEntries(path)
+	if err != nil || len(names) == 0 {
+		return ""
+	}
+
+	var order []string
+	counts := make(map[string]int)
+	isDir := make(map[string]bool)
+	for _, name := range names {
+		first, rest, nested := strings.Cut(strings.TrimPrefix(name, "./"), "/")
+		if first == "" {
+			continue
+		}
+		if _, seen := counts[first]; !seen {
+			order = append(order, first)
+			counts[first] = 0
+		}
+		if nested {
+			isDir[first] = true
+			if rest != "" && !strings.HasSuffix(rest, "/") {
+				counts[first]++
+			}
+		}
+	}
+
+	lines := []string{fmt.Sprintf("%s entries", formatCount(len(names)))}
+	for _, name := range order[:min(len(order), archivePreviewEntries)] {
+		if isDir[name] {
+			lines = append(lines, fmt.Sprintf("%s %s/ (%d files)", getFileIcon(name, true), sanitizeName(name), counts[name]))
+		} else {
+			lines = append(lines, getFileIcon(name, false)+" "+sanitizeName(name))
+		}
+	}
+	if more := len(order) - archivePreviewEntries; more > 0 {
+		lines = append(lines, fmt.Sprintf("+%d more", more))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// archiveEntries returns the name of every entry in a zip or tar archive.
+// A zip lists them in its central directory, but a tar has to be read from
+// start to end, so big tarballs are not looked into at all.
+func archiveEntries(path string) ([]string, error) {
+	if strings.HasSuffix(strings.ToLower(path), ".zip") {
+		reader, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		names := make([]string, len(reader.File))
+		for i, entry := range reader.File {
+			names[i] = entry.Name
+		}
+		return names, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err != nil || info.Size() > previewHardCap {
+		return nil, err
+	}
+
+	var r io.Reader = f
+	if name := strings.ToLower(path); strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, ".tgz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	var names []string
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return names, err
+		}
+		names = append(names, header.Name)
+	}
+}
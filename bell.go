@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// completeBells is how often --bell rings when a deletion run finishes, so
+// the end sounds different from a single file going.
+const completeBells = 3
+
+// ringBell queues times rings of the terminal bell with --bell; they go out
+// with the next frame. Nothing rings when standard output isn't a terminal,
+// where a stray \a would only end up in a file or pipe.
+func (m *model) ringBell(times int) {
+	if !m.cfg.Bell {
+		return
+	}
+	if info, err := os.Stdout.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return
+	}
+	if m.bells == nil {
+		m.bells = new(int)
+	}
+	*m.bells += times
+}
+
+// bellSequence hands the queued rings to the view and forgets them, so each
+// one is sent once however often the screen is redrawn afterwards. The
+// count is shared by all copies of the model, which lets View take it.
+func (m model) bellSequence() string {
+	if m.bells == nil || *m.bells == 0 {
+		return ""
+	}
+	seq := strings.Repeat("\a", *m.bells)
+	*m.bells = 0
+	return seq
+}
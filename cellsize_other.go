@@ -0,0 +1,8 @@
+//go:build !unix
+
+package main
+
+// cellPixels is not available on this platform.
+func cellPixels() (int, int) {
+	return 0, 0
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// stateDir is where dinder keeps caches, locks and other state it can
+// always rebuild.
+func stateDir() (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cache, "dinder"), nil
+}
+
+// runClean implements `dinder clean`, removing dinder's own cached state.
+// Locks held by a running instance are left alone.
+func runClean(args []string) error {
+	flags := flag.NewFlagSet("dinder clean", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "only show what would be removed")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	dir, err := stateDir()
+	if err != nil {
+		return err
+	}
+
+	var removed, kept int
+	var freed int64
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if strings.HasSuffix(path, ".lock") && liveLock(path) != nil {
+			kept++
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if *dryRun {
+			fmt.Printf("would remove %s (%s)\n", rel, formatSize(info.Size()))
+		} else if err := os.Remove(path); err != nil {
+			fmt.Fprintf(os.Stderr, "could not remove %s: %v\n", rel, err)
+			return nil
+		}
+		removed++
+		freed += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !*dryRun {
+		removeEmptyDirs(dir)
+	}
+
+	verb := "Removed"
+	if *dryRun {
+		verb = "Would remove"
+	}
+	fmt.Printf("%s %d files, %s freed", verb, removed, formatSize(freed))
+	if kept > 0 {
+		fmt.Printf(" (%d locks in use were kept)", kept)
+	}
+	fmt.Println()
+	return nil
+}
+
+// removeEmptyDirs deletes empty directories below and including root,
+// deepest first.
+func removeEmptyDirs(root string) {
+	var dirs []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	for i := len(dirs) - 1; i >= 0; i-- {
+		os.Remove(dirs[i]) // fails harmlessly when not empty
+	}
+}
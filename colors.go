@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// colorProfile is the color depth of the terminal we are drawing on. It is
+// detected once at startup and shared by lipgloss and the syntax highlighter.
+var colorProfile = termenv.ANSI256
+
+// setupColors detects the terminal's color support from the environment
+// (NO_COLOR, CLICOLOR, COLORTERM, TERM and whether stdout is a TTY) and
+// applies it to the whole UI.
+func setupColors() {
+	colorProfile = termenv.NewOutput(os.Stdout).EnvColorProfile()
+	lipgloss.SetColorProfile(colorProfile)
+}
+
+// chromaFormatterName picks the chroma terminal formatter matching the
+// detected color profile. An empty name means no highlighting at all.
+func chromaFormatterName(profile termenv.Profile) string {
+	switch profile {
+	case termenv.TrueColor:
+		return "terminal16m"
+	case termenv.ANSI256:
+		return "terminal256"
+	case termenv.ANSI:
+		return "terminal16"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+type Config struct {
+	Dir                string
+	Dirs               []string
+	Recursive          bool
+	PreviewWidth       int
+	PreviewHeight      int
+	PreviewRatio       float64
+	PreviewLines       int
+	PreviewMaxSize     int64
+	NoPreviewExts      []string
+	Watch              bool
+	OrganizeKept       organizeRules
+	MoveTo             string
+	Throttle           float64
+	Audit              bool
+	NoDirDelete        bool
+	Sort               string
+	JunkWeights        junkWeights
+	ByAge              bool
+	Archive            string
+	AuditReport        string
+	Permanent          bool
+	RespectGitignore   bool
+	MinSize            int64
+	OlderThan          time.Duration
+	NewerThan          time.Duration
+	Theme              string
+	DryRun             bool
+	SaveSession        string
+	Resume             string
+	Keys               keyMap
+	KeyWarnings        []string
+	Exclude            patternList
+	Exts               []string
+	KeepFile           string
+	Depth              int
+	Group              bool
+	FindDupes          bool
+	Yes                bool
+	Report             string
+	Stdin              bool
+	Paths              []string
+	NoPreview          bool
+	IncludeHidden      bool
+	Summary            bool
+	FollowSymlinks     bool
+	Suggest            bool
+	InteractiveConfirm bool
+	List               bool
+	NoTitle            bool
+	Force              bool
+	ListOnly           bool
+	JSON               bool
+	Bell               bool
+	Icons              map[string]string
+	ASCII              bool
+	Mouse              bool
+}
+
+func parseConfig(args []string) (Config, error) {
+	var cfg Config
+
+	fs := flag.NewFlagSet("dinder", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: dinder [flags] [directory... | archive.zip]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.BoolVar(&cfg.Recursive, "recursive", false, "walk into subdirectories and review every file individually")
+	fs.BoolVar(&cfg.Recursive, "r", false, "shorthand for --recursive")
+	fs.BoolVar(&cfg.FollowSymlinks, "follow-symlinks", false, "with --recursive, also walk into symlinked directories (loops are detected)")
+	fs.IntVar(&cfg.Depth, "depth", 0, "walk at most N levels deep and review deeper folders whole; implies --recursive (0 = no limit)")
+	fs.IntVar(&cfg.PreviewWidth, "preview-width", 0, "width of the code preview box (0 = fit terminal)")
+	fs.IntVar(&cfg.PreviewHeight, "preview-height", 0, "height of the code preview box (0 = derive from width and ratio)")
+	fs.Float64Var(&cfg.PreviewRatio, "preview-ratio", 80.0/12.0, "width-to-height ratio used when no height is given")
+	fs.IntVar(&cfg.PreviewLines, "preview-lines", 15, "maximum number of preview lines to show")
+	cfg.PreviewMaxSize = 10 << 10
+	fs.Var((*sizeFlag)(&cfg.PreviewMaxSize), "preview-max-size", "only preview files smaller than this (e.g. 10K, 2M)")
+	fs.BoolVar(&cfg.NoPreview, "no-preview", false, "never read file contents for previews, which makes scanning big trees much faster")
+	noPreviewExts := fs.String("no-preview-ext", ".min.js,.min.css,.map,.lock,.pb.go", "comma-separated file suffixes that never get a preview")
+	fs.Var(&cfg.OrganizeKept, "organize-kept", "move kept files matching TYPE=DIR into DIR (repeatable)")
+	fs.StringVar(&cfg.MoveTo, "move-to", "", "directory the m/M review actions move files into")
+	fs.Float64Var(&cfg.Throttle, "throttle", 0, "limit deletions to N operations per second (0 = unlimited)")
+	fs.BoolVar(&cfg.Audit, "audit", false, "review without ever deleting; write a decisions report instead")
+	fs.StringVar(&cfg.AuditReport, "audit-report", "dinder-audit.json", "where --audit writes its report")
+	fs.StringVar(&cfg.Report, "report", "", "after deleting, write a JSON report of what was deleted or failed to this file")
+	fs.BoolVar(&cfg.DryRun, "dry-run", false, "go through the whole flow but delete and move nothing; print what would have happened")
+	fs.BoolVar(&cfg.InteractiveConfirm, "interactive-confirm", false, "after confirming, answer y/n for every file marked for deletion before anything is deleted")
+	fs.BoolVar(&cfg.Yes, "yes", false, "skip the confirmation screen once every file has been reviewed (undecided files are never deleted)")
+	fs.BoolVar(&cfg.Yes, "y", false, "shorthand for --yes")
+	fs.BoolVar(&cfg.Bell, "bell", false, "ring the terminal bell as files are deleted, and three times when deletion finishes")
+	fs.BoolVar(&cfg.Force, "force", false, "allow reviewing a filesystem root, your home directory or a system directory")
+	fs.BoolVar(&cfg.Permanent, "permanent", false, "delete files outright instead of moving them to the trash")
+	fs.BoolVar(&cfg.NoDirDelete, "no-dir-delete", false, "never allow directories to be marked for deletion")
+	fs.StringVar(&cfg.Sort, "sort", "", "review order: size, size-desc, date, date-desc, name or junk (most likely junk first)")
+	cfg.JunkWeights = defaultJunkWeights
+	fs.Var(&cfg.JunkWeights, "junk-weights", "weights for --sort junk and --suggest, e.g. size=1,age=2,type=1")
+	fs.BoolVar(&cfg.Suggest, "suggest", false, "highlight files that look deletable (old, large or temporary); nothing is decided for you")
+	fs.BoolVar(&cfg.ByAge, "by-age", false, "review files grouped by age, oldest group first")
+	fs.BoolVar(&cfg.FindDupes, "find-dupes", false, "review only files whose content is duplicated, one set of copies at a time")
+	fs.BoolVar(&cfg.Summary, "summary", false, "show a breakdown of what was found by file type before review starts")
+	fs.BoolVar(&cfg.Group, "group", false, "review files by type, with one keep/delete decision per category")
+	fs.BoolVar(&cfg.List, "list", false, "triage in a scrollable table of all files instead of one card at a time")
+	fs.BoolVar(&cfg.ListOnly, "list-only", false, "print the files that would be reviewed, one path per line, and exit without starting the review")
+	fs.BoolVar(&cfg.JSON, "json", false, "with --list-only, print the files as a JSON array with all their details")
+	fs.Var((*sizeFlag)(&cfg.MinSize), "min-size", "only review files at least this large, e.g. 10M or 500K (0 = everything)")
+	fs.Var((*ageFlag)(&cfg.OlderThan), "older-than", "only review files last modified longer ago than this, e.g. 30d, 6mo, 1y")
+	fs.Var((*ageFlag)(&cfg.NewerThan), "newer-than", "only review files modified within this long, e.g. 12h, 2w")
+	exts := fs.String("ext", "", "only review files with these comma-separated extensions, e.g. log,tmp")
+	fs.Var(&cfg.Exclude, "exclude", "never review files whose name or relative path matches this glob (repeatable, env: DINDER_IGNORE)")
+	fs.BoolVar(&cfg.IncludeHidden, "include-hidden", false, "also review dotfiles, and walk into hidden directories with --recursive")
+	fs.BoolVar(&cfg.RespectGitignore, "respect-gitignore", false, "leave out anything matched by the directory's .gitignore")
+	fs.BoolVar(&cfg.ASCII, "ascii", plainTerminal(), "show plain markers such as [D] and [G] instead of emoji icons (default when the locale isn't UTF-8)")
+	fs.BoolVar(&cfg.Mouse, "mouse", false, "click the review buttons with the mouse; selecting text in the terminal then usually needs shift")
+	fs.BoolVar(&cfg.NoTitle, "no-title", false, "don't show review progress in the terminal window title")
+	fs.StringVar(&cfg.Theme, "theme", envOr("DINDER_THEME", "monokai"), "syntax highlighting style, any chroma style name (env: DINDER_THEME)")
+	fs.StringVar(&cfg.SaveSession, "save-session", "", "save review decisions to this JSON file on exit")
+	fs.StringVar(&cfg.Resume, "resume", "", "continue the review saved in this session file (saved back to it on exit)")
+	fs.BoolVar(&cfg.Stdin, "stdin", false, "review exactly the newline-separated paths read from standard input, e.g. fd -e log | dinder --stdin")
+	fs.BoolVar(&cfg.Watch, "watch", false, "keep watching the directory and queue new files as they appear")
+	fs.BoolVar(&cfg.Watch, "follow", false, "alias for --watch")
+
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	// Patterns from the environment apply on top of any --exclude flags
+	for _, pattern := range strings.FieldsFunc(os.Getenv("DINDER_IGNORE"), func(r rune) bool { return r == ':' || r == ',' }) {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		if err := cfg.Exclude.Set(pattern); err != nil {
+			return cfg, fmt.Errorf("DINDER_IGNORE: %v", err)
+		}
+	}
+
+	if cfg.PreviewWidth < 0 || cfg.PreviewHeight < 0 {
+		return cfg, fmt.Errorf("preview dimensions must not be negative")
+	}
+	if cfg.PreviewRatio <= 0 {
+		return cfg, fmt.Errorf("preview ratio must be positive")
+	}
+	if cfg.Depth < 0 {
+		return cfg, fmt.Errorf("depth must not be negative")
+	}
+	if cfg.Depth > 0 {
+		cfg.Recursive = true
+	}
+	if cfg.FollowSymlinks && !cfg.Recursive {
+		return cfg, fmt.Errorf("--follow-symlinks only applies to --recursive or --depth scans")
+	}
+	if cfg.Throttle < 0 {
+		return cfg, fmt.Errorf("throttle must not be negative")
+	}
+	cfg.NoPreviewExts = splitList(*noPreviewExts)
+	for _, ext := range splitList(*exts) {
+		cfg.Exts = append(cfg.Exts, "."+strings.TrimPrefix(strings.ToLower(ext), "."))
+	}
+
+	cfg.Dir = "."
+	for _, target := range fs.Args() {
+		info, err := os.Stat(target)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return cfg, fmt.Errorf("%s does not exist", target)
+			}
+			return cfg, err
+		}
+		switch {
+		case info.IsDir():
+			cfg.Dirs = append(cfg.Dirs, target)
+		case isArchiveTarget(target) && fs.NArg() == 1:
+			cfg.Archive = target
+		case isArchiveTarget(target):
+			return cfg, fmt.Errorf("%s: an archive can only be reviewed on its own", target)
+		default:
+			return cfg, fmt.Errorf("%s is not a directory", target)
+		}
+	}
+	if len(cfg.Dirs) == 0 {
+		cfg.Dirs = []string{cfg.Dir}
+	}
+	cfg.Dir = cfg.Dirs[0]
+	if len(cfg.Dirs) > 1 && cfg.Watch {
+		return cfg, fmt.Errorf("--watch follows a single directory and can't be used with several")
+	}
+
+	if cfg.Stdin && (fs.NArg() > 0 || cfg.Watch) {
+		return cfg, fmt.Errorf("--stdin reviews the paths it reads and can't be combined with a directory, an archive or --watch")
+	}
+
+	if cfg.SaveSession == "" {
+		cfg.SaveSession = cfg.Resume
+	}
+
+	if cfg.MoveTo != "" {
+		cfg.MoveTo = expandDest(cfg.MoveTo, FileItem{})
+	}
+	if !validSortMode(cfg.Sort) {
+		return cfg, fmt.Errorf("unknown sort order %q", cfg.Sort)
+	}
+	if cfg.FindDupes && (cfg.Sort != "" || cfg.ByAge || cfg.Group || cfg.Watch || cfg.Archive != "") {
+		return cfg, fmt.Errorf("--find-dupes reviews copies side by side and can't be combined with --sort, --by-age, --group, --watch or an archive")
+	}
+	if cfg.InteractiveConfirm && cfg.Yes {
+		return cfg, fmt.Errorf("--interactive-confirm and --yes can't be combined")
+	}
+	if cfg.List && (cfg.Watch || cfg.Group || cfg.ByAge) {
+		return cfg, fmt.Errorf("--list can't be combined with --watch, --group or --by-age")
+	}
+	if cfg.JSON && !cfg.ListOnly {
+		return cfg, fmt.Errorf("--json only applies to --list-only")
+	}
+	if cfg.ListOnly && cfg.Watch {
+		return cfg, fmt.Errorf("--list-only prints one scan and can't be combined with --watch")
+	}
+	if cfg.Group && cfg.ByAge {
+		return cfg, fmt.Errorf("--group and --by-age can't be combined")
+	}
+	if cfg.OlderThan > 0 && cfg.NewerThan > 0 && cfg.OlderThan >= cfg.NewerThan {
+		return cfg, fmt.Errorf("--older-than must be shorter than --newer-than, or no file can match")
+	}
+	cfg.KeepFile = keepListPath()
+	var err error
+	cfg.Keys, cfg.KeyWarnings, err = loadKeyMap(keyConfigPath())
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Icons, err = loadIcons(keyConfigPath())
+	if err != nil {
+		return cfg, err
+	}
+
+	if !slices.Contains(styles.Names(), cfg.Theme) {
+		return cfg, fmt.Errorf("unknown theme %q, available themes:\n  %s", cfg.Theme, strings.Join(styles.Names(), "\n  "))
+	}
+	if cfg.PreviewLines < 1 {
+		return cfg, fmt.Errorf("preview lines must be at least 1")
+	}
+
+	return cfg, nil
+}
+
+// patternList collects the glob patterns of a repeatable flag.
+type patternList []string
+
+func (p *patternList) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *patternList) Set(value string) error {
+	if _, err := filepath.Match(value, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %v", value, err)
+	}
+	*p = append(*p, value)
+	return nil
+}
+
+// envOr returns the environment variable key, or fallback when it is unset.
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// sizeFlag is an int64 byte count that accepts human-readable values such as
+// "500K", "10M" or "1.5GB" on the command line.
+type sizeFlag int64
+
+func (f *sizeFlag) String() string {
+	return formatSize(int64(*f))
+}
+
+func (f *sizeFlag) Set(value string) error {
+	size, err := parseSize(value)
+	if err != nil {
+		return err
+	}
+	*f = sizeFlag(size)
+	return nil
+}
+
+func parseSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I")
+
+	multiplier := int64(1)
+	if s != "" {
+		if i := strings.IndexByte("KMGTP", s[len(s)-1]); i >= 0 {
+			multiplier = int64(1) << (10 * (i + 1))
+			s = s[:len(s)-1]
+		}
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// ageFlag is a duration that also accepts the calendar-ish units people use
+// for file ages: d (days), w (weeks), mo (months) and y (years).
+type ageFlag time.Duration
+
+func (f *ageFlag) String() string {
+	if *f == 0 {
+		return ""
+	}
+	return time.Duration(*f).String()
+}
+
+func (f *ageFlag) Set(value string) error {
+	age, err := parseAge(value)
+	if err != nil {
+		return err
+	}
+	*f = ageFlag(age)
+	return nil
+}
+
+var ageUnits = []struct {
+	suffix string
+	unit   time.Duration
+}{
+	{"mo", 30 * 24 * time.Hour},
+	{"d", 24 * time.Hour},
+	{"w", 7 * 24 * time.Hour},
+	{"y", 365 * 24 * time.Hour},
+}
+
+func parseAge(value string) (time.Duration, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	for _, u := range ageUnits {
+		if number, ok := strings.CutSuffix(s, u.suffix); ok {
+			n, err := strconv.ParseFloat(number, 64)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid age %q", value)
+			}
+			return time.Duration(n * float64(u.unit)), nil
+		}
+	}
+
+	// Plain Go durations such as 36h still work
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 30d, 2w, 6mo or 1y)", value)
+	}
+	return d, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
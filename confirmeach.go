@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// With --interactive-confirm, confirming the deletion first walks through
+// every file in m.toDelete once more. Files answered with n are kept after
+// all; the rest are deleted once the last one is answered.
+
+func (m model) startConfirmEach() (tea.Model, tea.Cmd) {
+	m.confirmEach = true
+	m.confirmEachAt = 0
+	return m, nil
+}
+
+func (m model) handleConfirmEachInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		m.confirmEachAt++
+	case "n":
+		if i := m.indexOfFile(m.toDelete[m.confirmEachAt].Path); i >= 0 {
+			m.decide(i, true)
+		}
+		m.confirmEachAt++
+	case "esc":
+		// Back to the full list; files rescued so far stay kept
+		m.confirmEach = false
+		m.prepareConfirmation()
+		return m, nil
+	case "q":
+		return m, tea.Quit
+	default:
+		return m, nil
+	}
+
+	if m.confirmEachAt < len(m.toDelete) {
+		return m, nil
+	}
+	m.confirmEach = false
+	m.prepareConfirmation()
+	if len(m.toDelete) == 0 {
+		// Everything was rescued; leave any moves to the confirmation screen
+		return m, nil
+	}
+	return m.startDeletion()
+}
+
+func (m model) confirmEachView() string {
+	file := m.toDelete[m.confirmEachAt]
+	lines := m.deleteLines(file)
+	if age := time.Since(file.ModTime); age < recentlyModified {
+		lines = append(lines, warningStyle.Render("      ⚠ modified "+formatAgo(age)))
+	}
+	if problem, ok := m.deleteProblems[file.Path]; ok {
+		lines = append(lines, warningStyle.Render("      ⚠ will likely fail: "+problem))
+	}
+
+	prompt := lipgloss.JoinHorizontal(lipgloss.Top,
+		deleteButtonStyle.Render("✗ Delete it (y)"), "  ", keepButtonStyle.Render("✓ Keep it (n)"))
+	return fmt.Sprintf("\n%s\n\nFile %d of %d marked for deletion:\n\n%s\n\n%s\n\nesc=back to the list | q=quit without deleting",
+		titleStyle.Render("Final Check"), m.confirmEachAt+1, len(m.toDelete), strings.Join(lines, "\n"), prompt)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// findDuplicates narrows files down to the sets of files with identical
+// content, each set kept together and the sets that waste the most space
+// first. Only files that share their size with another file are hashed.
+func findDuplicates(files []FileItem) []FileItem {
+	bySize := make(map[int64][]int)
+	for i, file := range files {
+		if file.IsDir || file.IsSymlink || file.Size == 0 {
+			continue
+		}
+		bySize[file.Size] = append(bySize[file.Size], i)
+	}
+	var candidates []int
+	for _, indexes := range bySize {
+		if len(indexes) > 1 {
+			candidates = append(candidates, indexes...)
+		}
+	}
+	sort.Ints(candidates)
+
+	cache := loadHashCache()
+	defer cache.Save()
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range runtime.NumCPU() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				// Unreadable files simply can't be matched
+				files[i].Hash, _ = cache.Hash(files[i].Path, files[i].Size, files[i].ModTime)
+			}
+		}()
+	}
+	for _, i := range candidates {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	byHash := make(map[string][]FileItem)
+	var order []string
+	for _, i := range candidates {
+		file := files[i]
+		if file.Hash == "" {
+			continue
+		}
+		if _, ok := byHash[file.Hash]; !ok {
+			order = append(order, file.Hash)
+		}
+		byHash[file.Hash] = append(byHash[file.Hash], file)
+	}
+
+	wasted := func(hash string) int64 {
+		set := byHash[hash]
+		return set[0].Size * int64(len(set)-1)
+	}
+	sort.SliceStable(order, func(i, j int) bool { return wasted(order[i]) > wasted(order[j]) })
+
+	var dupes []FileItem
+	for _, hash := range order {
+		if set := byHash[hash]; len(set) > 1 {
+			dupes = append(dupes, set...)
+		}
+	}
+	return dupes
+}
+
+// duplicatesOf lists the indexes of the other files with the same content
+// as m.files[i].
+func (m model) duplicatesOf(i int) []int {
+	var indexes []int
+	if m.files[i].Hash == "" {
+		return nil
+	}
+	for j, file := range m.files {
+		if j != i && file.Hash == m.files[i].Hash {
+			indexes = append(indexes, j)
+		}
+	}
+	return indexes
+}
+
+// keepOnlyCopy keeps the current file and marks every other undecided copy
+// of it for deletion.
+func (m model) keepOnlyCopy() (tea.Model, tea.Cmd) {
+	others := m.duplicatesOf(m.currentFile)
+	if len(others) == 0 {
+		return m, nil
+	}
+	m.beginUndo()
+	m.remember(m.currentFile)
+	m.decide(m.currentFile, true)
+	for _, i := range others {
+		if !m.files[i].Decided && m.canDelete(m.files[i]) {
+			m.remember(i)
+			m.files[i].Skipped = false
+			m.decide(i, false)
+		}
+	}
+	return m.nextFile()
+}
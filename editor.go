@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type editorFinishedMsg struct {
+	path string
+	err  error
+}
+
+// editorCommand is $EDITOR, else $PAGER, else less. The variable may carry
+// arguments, as in EDITOR="code --wait".
+func editorCommand() []string {
+	for _, env := range []string{"EDITOR", "PAGER"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"less"}
+}
+
+// openInEditor hands the terminal to an editor or pager for the current file
+// and comes back to the same card when it exits.
+func (m model) openInEditor() (tea.Model, tea.Cmd) {
+	if m.cfg.Archive != "" {
+		// Archive entries don't exist on disk to be opened
+		return m, nil
+	}
+	path := m.files[m.currentFile].Path
+	args := append(editorCommand(), path)
+	cmd := exec.Command(args[0], args[1:]...)
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorFinishedMsg{path: path, err: err}
+	})
+}
+
+// handleEditorFinished refreshes the file, which may have been edited.
+func (m model) handleEditorFinished(msg editorFinishedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("Could not open %s: %v", sanitizeName(msg.path), msg.err)
+	}
+	i := m.indexOfFile(msg.path)
+	if i < 0 || m.files[i].Decided {
+		return m, nil
+	}
+	if info, err := os.Lstat(msg.path); err == nil {
+		item := newFileItem(msg.path, info, m.cfg)
+		item.Skipped = m.files[i].Skipped
+		item.DirFiles = m.files[i].DirFiles
+		item.Hash = m.files[i].Hash
+		if item.IsDir {
+			item.Size = m.files[i].Size
+		}
+		m.markSuggestion(&item)
+		m.files[i] = item
+	}
+	return m, nil
+}
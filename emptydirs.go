@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type emptyDirsMsg []string
+
+type emptyDirsRemovedMsg struct {
+	removed  int
+	failures []deleteFailure
+}
+
+// findEmptyDirs looks for folders the deletion left empty, so they can be
+// offered for removal before the completion screen.
+func (m model) findEmptyDirs() tea.Cmd {
+	deleted := make(map[string][]string)
+	for _, file := range m.files {
+		if file.Deleted {
+			root := file.Root
+			if root == "" {
+				root = m.cfg.Dir
+			}
+			deleted[root] = append(deleted[root], file.Path)
+		}
+	}
+	roots := m.cfg.Dirs
+	return func() tea.Msg {
+		var dirs []string
+		for _, root := range roots {
+			dirs = append(dirs, emptyDirs(root, deleted[root])...)
+		}
+		return emptyDirsMsg(dirs)
+	}
+}
+
+// emptyDirs returns the directories under root, but not root itself, that
+// hold nothing once the deleted paths are gone. A folder whose only
+// contents are other empty folders counts as empty too. The result is
+// ordered deepest first, so removing it in order always works.
+func emptyDirs(root string, deleted []string) []string {
+	candidates := make(map[string]bool)
+	for _, path := range deleted {
+		for dir := filepath.Dir(path); isBelow(root, dir) && !candidates[dir]; dir = filepath.Dir(dir) {
+			candidates[dir] = true
+		}
+	}
+
+	dirs := make([]string, 0, len(candidates))
+	for dir := range candidates {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		di, dj := strings.Count(dirs[i], string(filepath.Separator)), strings.Count(dirs[j], string(filepath.Separator))
+		if di != dj {
+			return di > dj
+		}
+		return dirs[i] < dirs[j]
+	})
+
+	empty := make(map[string]bool)
+	var result []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		isEmpty := true
+		for _, entry := range entries {
+			if !entry.IsDir() || !empty[filepath.Join(dir, entry.Name())] {
+				isEmpty = false
+				break
+			}
+		}
+		if isEmpty {
+			empty[dir] = true
+			result = append(result, dir)
+		}
+	}
+	return result
+}
+
+// isBelow reports whether path lies strictly inside root.
+func isBelow(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// removeEmptyDirs deletes the offered folders. os.Remove refuses anything
+// that gained an entry in the meantime, so nothing but empty folders goes.
+func (m model) removeEmptyDirs() tea.Cmd {
+	dirs := m.emptyDirs
+	return func() tea.Msg {
+		var result emptyDirsRemovedMsg
+		for _, dir := range dirs {
+			if err := os.Remove(dir); err != nil {
+				result.failures = append(result.failures, deleteFailure{
+					File: FileItem{Path: dir, Name: filepath.Base(dir), IsDir: true},
+					Err:  err,
+				})
+				continue
+			}
+			result.removed++
+		}
+		return result
+	}
+}
+
+func (m model) handleEmptyDirsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		return m, m.removeEmptyDirs()
+	case "n", "q", "esc":
+		m.emptyDirs = nil
+		m.screen = ScreenComplete
+	}
+	return m, nil
+}
+
+func (m model) emptyDirsView() string {
+	lines := make([]string, len(m.emptyDirs))
+	for i, dir := range m.emptyDirs {
+		lines[i] = "  📁 " + sanitizeName(m.displayPath(dir))
+	}
+	prompt := lipgloss.JoinHorizontal(lipgloss.Top,
+		deleteButtonStyle.Render("✗ Remove them (enter)"), "  ", buttonStyle.Render("Leave them (n)"))
+	return fmt.Sprintf("\n%s\n\nThese folders are empty now (%d):\n%s\n\n%s",
+		titleStyle.Render("Empty Folders"), len(m.emptyDirs), strings.Join(lines, "\n"), prompt)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// extStatsShown is how many extensions the Complete screen lists before
+// rolling the rest up into "others".
+const extStatsShown = 8
+
+type extStat struct {
+	ext   string
+	count int
+	size  int64
+}
+
+// removedByExt groups the deleted files (with --dry-run, the ones that
+// would have been) by extension, biggest share of the freed space first,
+// e.g. "12 .log (340 MB), 4 .tmp (2.0 MB)".
+func (m model) removedByExt() string {
+	archiveDone := m.cfg.Archive != "" && m.deletedCount > 0
+	stats := make(map[string]*extStat)
+	for _, file := range m.toDelete {
+		i := m.indexOfFile(file.Path)
+		removed := archiveDone || (i >= 0 && m.files[i].Deleted) || slices.Contains(m.wouldDelete, file.Path)
+		if !removed {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(file.Name))
+		switch {
+		case file.IsDir && !file.IsSymlink:
+			ext = "folders"
+		case ext == "":
+			ext = "no extension"
+		}
+		if stats[ext] == nil {
+			stats[ext] = &extStat{ext: ext}
+		}
+		stats[ext].count++
+		stats[ext].size += m.deleteSize(file)
+	}
+
+	sorted := make([]extStat, 0, len(stats))
+	for _, stat := range stats {
+		sorted = append(sorted, *stat)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].size != sorted[j].size {
+			return sorted[i].size > sorted[j].size
+		}
+		return sorted[i].ext < sorted[j].ext
+	})
+
+	var parts []string
+	var others extStat
+	for i, stat := range sorted {
+		if i < extStatsShown {
+			parts = append(parts, fmt.Sprintf("%d %s (%s)", stat.count, stat.ext, formatSize(stat.size)))
+			continue
+		}
+		others.count += stat.count
+		others.size += stat.size
+	}
+	if others.count > 0 {
+		parts = append(parts, fmt.Sprintf("%d others (%s)", others.count, formatSize(others.size)))
+	}
+	return strings.Join(parts, ", ")
+}
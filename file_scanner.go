@@ -2,101 +2,640 @@ package main
 
 import (
 	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type FileItem struct {
-	Path     string
-	Name     string
-	IsDir    bool
-	Size     int64
-	ModTime  time.Time
-	Preview  string
-	Keep     bool
-	Decided  bool
-	Skipped  bool
+	Path            string
+	Name            string
+	IsDir           bool
+	DirFiles        int
+	IsSymlink       bool
+	LinkTarget      string
+	ReadOnly        bool
+	Size            int64
+	ModTime         time.Time
+	Mode            fs.FileMode
+	Owner           string
+	Group           string
+	Preview         string `json:"-"`
+	PreviewTooLarge bool   `json:"-"`
+	PreviewLines    int    `json:"-"`
+	TotalLines      int    `json:"-"`
+	MoreLines       bool   `json:"-"`
+	ImageInfo       string `json:"-"`
+	Graphic         string `json:"-"`
+	Language        string `json:"-"`
+	Keep            bool
+	Decided         bool
+	Skipped         bool
+	Move            bool
+	LinkBack        bool
+	Deleted         bool
+	Hash            string
+	Suggested       bool
+	Root            string
 }
 
-func scanDirectory(dir string) ([]FileItem, error) {
+func scanDirectory(dir string, cfg Config) ([]FileItem, error) {
 	var items []FileItem
-	
-	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
+
+	err := walkDirectory(dir, cfg, func(item FileItem) {
+		items = append(items, item)
+	}, nil)
+
+	return items, err
+}
+
+// scanError is a path the scan had to leave out because it couldn't be read.
+type scanError struct {
+	Path string
+	Err  error
+}
+
+// scanWorkers is how many entries walkDirectory stats and previews at once.
+var scanWorkers = runtime.NumCPU()
+
+// walkDirectory scans dir and hands every reviewable entry to fn as soon as
+// it is found, so callers can stream results instead of waiting for the
+// whole tree.
+//
+// Stat-ing entries and reading their previews is spread over a pool of
+// workers, one per CPU, while fn still receives items in walk order (sorted
+// by path within each directory), so the review sequence stays stable.
+//
+// Paths that can't be read are handed to onErr, if set, and skipped; only
+// failing to read dir itself ends the scan.
+func walkDirectory(dir string, cfg Config, fn func(FileItem), onErr func(scanError)) error {
+	filter, err := newScanFilter(dir, cfg)
+	if err != nil {
+		return err
+	}
+
+	workers := scanWorkers
+	jobs := make(chan func(), workers)
+	ordered := make(chan chan scanResult, workers*4)
+
+	for range workers {
+		go func() {
+			for job := range jobs {
+				job()
+			}
+		}()
+	}
+
+	var walkErr error
+	go func() {
+		defer close(ordered)
+		defer close(jobs)
+
+		// With --follow-symlinks every directory walked into is remembered
+		// by device and inode. A link back up the tree, or a second way
+		// into a directory already walked, is then reviewed as a single
+		// item instead of being walked again, so a symlink loop can never
+		// make the scan run forever
+		seen := make(map[string]bool)
+		firstVisit := func(path string) bool {
+			id, ok := dirIdentity(path)
+			if !ok || seen[id] {
+				return false
+			}
+			seen[id] = true
+			return true
+		}
+		firstVisit(dir)
+
+		var visit fs.WalkDirFunc
+		visit = func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == dir {
+					return err
+				}
+				// Reported in walk order along with everything else
+				out := make(chan scanResult, 1)
+				out <- scanResult{path: path, err: err}
+				ordered <- out
+				if d != nil && d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if path == dir {
+				return nil
+			}
+
+			if filter.skip(path, d) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			// In recursive mode directories are walked into and only their
+			// files are reviewed, one by one
+			if d.IsDir() && descends(dir, path, cfg) {
+				if cfg.FollowSymlinks && !firstVisit(path) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if cfg.FollowSymlinks && d.Type()&fs.ModeSymlink != 0 && descends(dir, path, cfg) {
+				if info, err := os.Stat(path); err == nil && info.IsDir() && firstVisit(path) {
+					return walkLink(path, visit)
+				}
+			}
+
+			out := make(chan scanResult, 1)
+			ordered <- out
+			jobs <- func() { out <- scanEntry(path, d, cfg) }
+
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
 		}
-		
-		if path == dir {
+		walkErr = filepath.WalkDir(dir, visit)
+	}()
+
+	// Results are taken in the order entries were walked, whichever worker
+	// finishes first
+	for out := range ordered {
+		result := <-out
+		if result.err != nil {
+			if onErr != nil {
+				onErr(scanError{Path: result.path, Err: result.err})
+			}
+			continue
+		}
+		if result.keep {
+			fn(result.item)
+		}
+	}
+
+	return walkErr
+}
+
+// walkLink walks the directory the symlink at link points to, handing every
+// entry to fn under the link's path, so files are reviewed and deleted
+// through the link.
+func walkLink(link string, fn fs.WalkDirFunc) error {
+	target, err := filepath.EvalSymlinks(link)
+	if err != nil {
+		return fn(link, nil, err)
+	}
+	return filepath.WalkDir(target, func(path string, d fs.DirEntry, err error) error {
+		if path == target {
+			if err != nil {
+				return fn(link, d, err)
+			}
 			return nil
 		}
-		
-		info, err := d.Info()
+		rel, relErr := filepath.Rel(target, path)
+		if relErr != nil {
+			return relErr
+		}
+		return fn(filepath.Join(link, rel), d, err)
+	})
+}
+
+// descends reports whether a recursive scan of root walks into the
+// directory at path, or stops there and reviews it as a single item because
+// it sits at the --depth limit.
+func descends(root, path string, cfg Config) bool {
+	if !cfg.Recursive {
+		return false
+	}
+	if cfg.Depth == 0 {
+		return true
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return true
+	}
+	return strings.Count(rel, string(filepath.Separator))+1 < cfg.Depth
+}
+
+type scanResult struct {
+	item FileItem
+	keep bool
+	path string
+	err  error
+}
+
+// scanEntry does the per-entry work of a scan: stat, preview and measuring
+// directories.
+func scanEntry(path string, d fs.DirEntry, cfg Config) scanResult {
+	info, err := d.Info()
+	if err != nil {
+		return scanResult{path: path, err: err}
+	}
+
+	item := newFileItem(path, info, cfg)
+	if item.IsDir {
+		// Directories are reviewed and deleted as a whole, so they are
+		// measured by everything inside them
+		item.DirFiles, item.Size = dirStats(path)
+		if len(cfg.Exts) > 0 && item.DirFiles > 0 && !dirContainsExt(path, cfg.Exts) {
+			// With --ext a directory only shows up when it holds a
+			// matching file, or nothing at all
+			return scanResult{}
+		}
+	}
+	return scanResult{item: item, keep: matchesFilters(item, cfg)}
+}
+
+// matchesFilters applies the filters that need a file's metadata, as
+// opposed to scanFilter which only looks at names.
+func matchesFilters(item FileItem, cfg Config) bool {
+	if item.Size < cfg.MinSize {
+		return false
+	}
+	if !item.IsDir && len(cfg.Exts) > 0 && !hasExt(item.Name, cfg.Exts) {
+		return false
+	}
+	age := time.Since(item.ModTime)
+	if cfg.OlderThan > 0 && age < cfg.OlderThan {
+		return false
+	}
+	if cfg.NewerThan > 0 && age > cfg.NewerThan {
+		return false
+	}
+	return true
+}
+
+// scanFilter decides which entries the scanner leaves out entirely.
+type scanFilter struct {
+	root      string
+	exclude   []string
+	gitignore *gitignore
+	keep      keepList
+	hidden    bool
+}
+
+func newScanFilter(dir string, cfg Config) (*scanFilter, error) {
+	filter := &scanFilter{root: dir, exclude: cfg.Exclude, hidden: cfg.IncludeHidden}
+	keep, err := loadKeepList(cfg.KeepFile)
+	if err != nil {
+		return nil, err
+	}
+	filter.keep = keep
+	if cfg.RespectGitignore {
+		ignore, err := loadGitignore(dir)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		
-		relPath, _ := filepath.Rel(dir, path)
-		if strings.HasPrefix(relPath, ".") {
-			if d.IsDir() {
+		filter.gitignore = ignore
+	}
+	return filter, nil
+}
+
+// skip reports whether the entry at path should not be reviewed. Skipped
+// directories are not walked into either.
+func (f *scanFilter) skip(path string, d fs.DirEntry) bool {
+	if strings.HasPrefix(d.Name(), ".") && !f.hidden {
+		return true
+	}
+	rel, err := filepath.Rel(f.root, path)
+	if err != nil {
+		rel = path
+	}
+	for _, pattern := range f.exclude {
+		if matched, _ := filepath.Match(pattern, d.Name()); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, rel); matched {
+			return true
+		}
+	}
+	if f.gitignore != nil && f.gitignore.Match(filepath.ToSlash(rel), d.IsDir()) {
+		return true
+	}
+	return f.keep.Match(path)
+}
+
+// estimateCount is a cheap count-only pass over dir that mirrors the
+// scanner's filters without stat-ing or reading any file.
+func estimateCount(dir string, cfg Config) int {
+	filter, err := newScanFilter(dir, cfg)
+	if err != nil {
+		return 0
+	}
+
+	if cfg.Recursive {
+		count := 0
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || path == dir {
+				return nil
+			}
+			if filter.skip(path, d) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.IsDir() {
+				count++
+			} else if !descends(dir, path, cfg) {
+				count++
 				return filepath.SkipDir
 			}
 			return nil
+		})
+		return count
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range entries {
+		if !filter.skip(filepath.Join(dir, entry.Name()), entry) {
+			count++
 		}
-		
-		preview := ""
-		if !d.IsDir() && info.Size() < 10240 { // Only preview files < 10KB
-			preview = getFilePreview(path)
+	}
+	return count
+}
+
+// previewHardCap is the size above which a file is never read for a preview
+// during scanning, whatever --preview-max-size says. The user can still ask
+// for it explicitly from the review card.
+const previewHardCap = 50 << 20
+
+// lineCountBytes is how far into a file previews count lines; longer files
+// are shown as having "N+ lines".
+const lineCountBytes = 4 << 20
+
+func newFileItem(path string, info fs.FileInfo, cfg Config) FileItem {
+	preview := ""
+	lines := 0
+	moreLines := false
+	tooLarge := false
+	isLink := info.Mode()&fs.ModeSymlink != 0
+	target := ""
+	if isLink {
+		// Only the link itself is ever looked at or removed, never what
+		// it points to
+		target, _ = os.Readlink(path)
+	}
+	binary := !isTextFile(path) && !isImageFile(path)
+	if cfg.NoPreview {
+		// Nothing is read at all, which is what makes big scans fast
+	} else if info.IsDir() && !isLink {
+		preview = getDirPreview(path)
+	} else if !info.IsDir() && !isLink && binary && !skipsPreview(path, cfg.NoPreviewExts) {
+		// The hex dump only reads the first few bytes and an archive listing
+		// limits itself, so size doesn't matter
+		preview, _, _ = getFilePreview(path, cfg.PreviewLines)
+	} else if !info.IsDir() && !isLink && info.Size() < cfg.PreviewMaxSize && !skipsPreview(path, cfg.NoPreviewExts) {
+		if info.Size() > previewHardCap {
+			tooLarge = true
+		} else if !isImageFile(path) {
+			// Thumbnails are decoded once the image is on screen
+			preview, lines, moreLines = getFilePreview(path, cfg.PreviewLines)
 		}
+	}
 
-		item := FileItem{
-			Path:    path,
-			Name:    d.Name(),
-			IsDir:   d.IsDir(),
-			Size:    info.Size(),
-			ModTime: info.ModTime(),
-			Preview: preview,
-			Keep:    false,
-			Decided: false,
-			Skipped: false,
+	owner, group := fileOwner(info)
+	language := ""
+	if isCodeFile(path) && !info.IsDir() && !isLink {
+		language = languageName(path)
+	}
+
+	return FileItem{
+		Path:            path,
+		Name:            info.Name(),
+		IsDir:           info.IsDir(),
+		IsSymlink:       isLink,
+		LinkTarget:      target,
+		ReadOnly:        !isLink && !canWrite(path),
+		Size:            info.Size(),
+		ModTime:         info.ModTime(),
+		Mode:            info.Mode(),
+		Owner:           owner,
+		Group:           group,
+		Preview:         preview,
+		PreviewTooLarge: tooLarge,
+		PreviewLines:    cfg.PreviewLines,
+		TotalLines:      lines,
+		MoreLines:       moreLines,
+		Language:        language,
+		Keep:            false,
+		Decided:         false,
+		Skipped:         false,
+	}
+}
+
+type dirSummary struct {
+	Files   int
+	Size    int64
+	Largest []FileItem
+}
+
+// summarizeDir walks a directory tree and reports how many files it holds,
+// their total size and the largest few of them.
+func summarizeDir(dir string, sample int) dirSummary {
+	var summary dirSummary
+	var files []FileItem
+
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
 		}
-		
-		items = append(items, item)
-		
-		if d.IsDir() {
-			return filepath.SkipDir
+		info, err := d.Info()
+		if err != nil {
+			return nil
 		}
-		
+		summary.Files++
+		summary.Size += info.Size()
+		files = append(files, FileItem{Path: path, Name: d.Name(), Size: info.Size()})
 		return nil
 	})
-	
-	return items, err
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Size > files[j].Size
+	})
+	summary.Largest = files[:min(sample, len(files))]
+
+	return summary
+}
+
+// dirStats counts the files below dir and adds up their sizes.
+func dirStats(dir string) (int, int64) {
+	files, size := 0, int64(0)
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files++
+			size += info.Size()
+		}
+		return nil
+	})
+	return files, size
+}
+
+// hasExt reports whether name ends in one of exts (lowercase, with dot).
+func hasExt(name string, exts []string) bool {
+	name = strings.ToLower(name)
+	for _, ext := range exts {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func dirContainsExt(dir string, exts []string) bool {
+	found := false
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && hasExt(d.Name(), exts) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// deleteProblem predicts why removing file would fail, or returns "" when
+// it looks fine. Removing an entry needs write access to the directory that
+// holds it, whatever the entry's own permissions are.
+func deleteProblem(file FileItem) string {
+	parent := filepath.Dir(file.Path)
+	if !canWrite(parent) {
+		return fmt.Sprintf("no write permission on %s", parent)
+	}
+	if file.IsDir && !file.IsSymlink && !canWrite(file.Path) {
+		return "no write permission inside the directory"
+	}
+	return ""
 }
 
-func getFilePreview(path string) string {
+// countRemaining counts the files still present below path, used to report
+// how much of a failed directory removal was left behind.
+func countRemaining(path string) int {
+	count := 0
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+// skipsPreview reports whether the file name ends in one of the configured
+// suffixes (".min.js", ".lock", ...) whose previews are never useful.
+func skipsPreview(path string, suffixes []string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, strings.ToLower(suffix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func getFilePreview(path string, codeLines int) (string, int, bool) {
+	if isListableArchive(path) {
+		return getArchivePreview(path), 0, false
+	}
 	if !isTextFile(path) {
+		return getBinaryPreview(path, codeLines), 0, false
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, false
+	}
+	defer file.Close()
+
+	return readPreview(file, path, codeLines)
+}
+
+// dirPreviewEntries is how many children a directory preview lists.
+const dirPreviewEntries = 10
+
+// getDirPreview lists the first entries directly inside a directory, with
+// a "+N more" line for the rest. Unlike the scan it includes hidden entries,
+// since deleting the directory takes those along too.
+func getDirPreview(path string) string {
+	entries, err := os.ReadDir(path)
+	if err != nil || len(entries) == 0 {
 		return ""
 	}
 
+	var lines []string
+	for _, entry := range entries[:min(len(entries), dirPreviewEntries)] {
+		name := sanitizeName(entry.Name())
+		if entry.IsDir() {
+			name += "/"
+		}
+		lines = append(lines, getFileIcon(entry.Name(), entry.IsDir())+" "+name)
+	}
+	if more := len(entries) - dirPreviewEntries; more > 0 {
+		lines = append(lines, fmt.Sprintf("+%d more", more))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// binaryPreviewBytes is how much of a binary file the hex dump shows at
+// most, 16 bytes per line.
+const binaryPreviewBytes = 256
+
+// getBinaryPreview renders the leading bytes of path like hexdump -C, which
+// is usually enough to recognize a file type by its magic number.
+func getBinaryPreview(path string, maxLines int) string {
 	file, err := os.Open(path)
 	if err != nil {
 		return ""
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	buf := make([]byte, min(binaryPreviewBytes, max(maxLines, 1)*16))
+	n, err := io.ReadFull(file, buf)
+	if n == 0 || (err != nil && err != io.ErrUnexpectedEOF) {
+		return ""
+	}
+	return strings.TrimRight(hex.Dump(buf[:n]), "\n")
+}
+
+// readPreview builds a preview from the start of r, and counts its lines.
+// The bool reports that counting stopped at lineCountBytes before the end.
+// path is only used to decide how the content should be treated.
+func readPreview(r io.Reader, path string, codeLines int) (string, int, bool) {
 	var lines []string
 	lineCount := 0
 	maxLines := 3
-	
+
 	// Show more lines for code files
 	if isCodeFile(path) {
-		maxLines = 15 // More lines for the dedicated code box
+		maxLines = codeLines // More lines for the dedicated code box
 	}
 
-	for scanner.Scan() && lineCount < maxLines {
+	// Keep reading past the preview to count the file's lines, but only up
+	// to lineCountBytes so a huge file can't stall the UI
+	limited := &io.LimitedReader{R: r, N: lineCountBytes + 1}
+	scanner := bufio.NewScanner(limited)
+	total := 0
+	for scanner.Scan() {
+		total++
+		if lineCount >= maxLines {
+			continue
+		}
 		line := scanner.Text()
 		if strings.TrimSpace(line) != "" || isCodeFile(path) {
 			lines = append(lines, line)
@@ -104,16 +643,31 @@ func getFilePreview(path string) string {
 		}
 	}
 
+	more := limited.N == 0
 	if len(lines) == 0 {
-		return ""
+		return "", total, more
 	}
 
 	preview := strings.Join(lines, "\n")
-	if len(preview) > 800 { // Allow more content for code files
-		preview = preview[:797] + "..."
-	}
+	maxBytes := max(800, maxLines*80) // Allow more content for code files
+	return truncatePreview(preview, maxBytes), total, more
+}
 
-	return preview
+// truncatePreview cuts preview to at most maxBytes, marking the cut with
+// "...". It never splits a UTF-8 character, and cuts at the end of a line
+// instead when that doesn't throw away more than half the text.
+func truncatePreview(preview string, maxBytes int) string {
+	if len(preview) <= maxBytes {
+		return preview
+	}
+	cut := maxBytes - 3
+	for cut > 0 && !utf8.RuneStart(preview[cut]) {
+		cut--
+	}
+	if nl := strings.LastIndexByte(preview[:cut], '\n'); nl > cut/2 {
+		return preview[:nl] + "\n..."
+	}
+	return preview[:cut] + "..."
 }
 
 func isTextFile(path string) bool {
@@ -157,141 +711,31 @@ func isCodeFile(path string) bool {
 	return false
 }
 
-func getFileIcon(path string, isDir bool) string {
-	if isDir {
-		return "📁"
+// fileCategory groups a file into a coarse type such as "images" or
+// "archives" based on its extension.
+func fileCategory(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	categories := map[string][]string{
+		"images":    {".jpg", ".jpeg", ".png", ".gif", ".svg", ".ico", ".webp", ".bmp", ".heic", ".tiff"},
+		"videos":    {".mp4", ".avi", ".mkv", ".mov", ".wmv", ".flv", ".webm"},
+		"audio":     {".mp3", ".wav", ".flac", ".m4a", ".ogg"},
+		"documents": {".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx", ".txt", ".md", ".odt", ".rtf"},
+		"archives":  {".zip", ".tar", ".gz", ".rar", ".7z", ".bz2", ".xz", ".tgz"},
+		"packages":  {".exe", ".app", ".deb", ".rpm", ".dmg", ".iso", ".msi", ".pkg"},
 	}
 
-	ext := strings.ToLower(filepath.Ext(path))
-	
-	iconMap := map[string]string{
-		// Code files
-		".go":     "🐹",
-		".js":     "🟨",
-		".ts":     "🔷",
-		".py":     "🐍",
-		".java":   "☕",
-		".c":      "🔧",
-		".cpp":    "🔧",
-		".h":      "📋",
-		".rs":     "🦀",
-		".php":    "🐘",
-		".rb":     "💎",
-		".swift":  "🍎",
-		".kt":     "🟣",
-		".scala":  "🔴",
-		
-		// Web files
-		".html":   "🌐",
-		".css":    "🎨",
-		".scss":   "🎨",
-		".sass":   "🎨",
-		".jsx":    "⚛️",
-		".tsx":    "⚛️",
-		".vue":    "💚",
-		
-		// Data files
-		".json":   "📋",
-		".xml":    "📋",
-		".yaml":   "📋",
-		".yml":    "📋",
-		".toml":   "📋",
-		".ini":    "⚙️",
-		".cfg":    "⚙️",
-		".conf":   "⚙️",
-		
-		// Documents
-		".md":     "📝",
-		".txt":    "📄",
-		".pdf":    "📕",
-		".doc":    "📘",
-		".docx":   "📘",
-		".xls":    "📗",
-		".xlsx":   "📗",
-		".ppt":    "📙",
-		".pptx":   "📙",
-		
-		// Images
-		".jpg":    "🖼️",
-		".jpeg":   "🖼️",
-		".png":    "🖼️",
-		".gif":    "🖼️",
-		".svg":    "🎨",
-		".ico":    "🖼️",
-		".webp":   "🖼️",
-		".bmp":    "🖼️",
-		
-		// Audio
-		".mp3":    "🎵",
-		".wav":    "🎵",
-		".flac":   "🎵",
-		".m4a":    "🎵",
-		".ogg":    "🎵",
-		
-		// Video
-		".mp4":    "🎬",
-		".avi":    "🎬",
-		".mkv":    "🎬",
-		".mov":    "🎬",
-		".wmv":    "🎬",
-		".flv":    "🎬",
-		".webm":   "🎬",
-		
-		// Archives
-		".zip":    "📦",
-		".tar":    "📦",
-		".gz":     "📦",
-		".rar":    "📦",
-		".7z":     "📦",
-		".bz2":    "📦",
-		".xz":     "📦",
-		
-		// Executables
-		".exe":    "⚡",
-		".app":    "📱",
-		".deb":    "📦",
-		".rpm":    "📦",
-		".dmg":    "💿",
-		".iso":    "💿",
-		
-		// System files
-		".log":    "📋",
-		".tmp":    "🗑️",
-		".cache":  "🗑️",
-		".bak":    "💾",
-		".old":    "💾",
-		
-		// Shell scripts
-		".sh":     "🐚",
-		".bash":   "🐚",
-		".zsh":    "🐚",
-		".fish":   "🐚",
-		".bat":    "🖥️",
-		".ps1":    "🔷",
-		
-		// Database
-		".db":     "🗄️",
-		".sqlite": "🗄️",
-		".sql":    "🗄️",
-		
-		// Git
-		".git":    "🔀",
-		
-		// Docker
-		"dockerfile": "🐳",
-	}
-	
-	// Check for special filenames without extensions
-	filename := strings.ToLower(filepath.Base(path))
-	if filename == "dockerfile" || filename == "makefile" || filename == "readme" {
-		if icon, exists := iconMap[filename]; exists {
-			return icon
+	for category, exts := range categories {
+		for _, e := range exts {
+			if ext == e {
+				return category
+			}
 		}
 	}
-	
-	if icon, exists := iconMap[ext]; exists {
-		return icon
+
+	if isCodeFile(path) {
+		return "code"
 	}
-	
-	return "📄" // Default file icon
+
+	return "other"
 }
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/lassejlv/dinder/internal/fixture"
+)
+
+// buildScanTree writes dirs directories of files small text files each below
+// root, enough for the scan's per-entry work to dominate.
+func buildScanTree(tb testing.TB, root string, dirs, files int) {
+	tb.Helper()
+	body := strings.Repeat("some line of text in a file\n", 40)
+	for d := range dirs {
+		dir := filepath.Join(root, fmt.Sprintf("dir%03d", d))
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			tb.Fatal(err)
+		}
+		for f := range files {
+			path := filepath.Join(dir, fmt.Sprintf("file%03d.txt", f))
+			if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkScanDirectory(b *testing.B) {
+	root := b.TempDir()
+	buildScanTree(b, root, 40, 100)
+	cfg := Config{Recursive: true, PreviewLines: 15, PreviewMaxSize: 10 << 10}
+
+	counts := []int{1}
+	if n := runtime.NumCPU(); n > 1 {
+		counts = append(counts, n)
+	}
+	defer func(workers int) { scanWorkers = workers }(scanWorkers)
+	for _, workers := range counts {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			scanWorkers = workers
+			for b.Loop() {
+				items, err := scanDirectory(root, cfg)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(items) != 4000 {
+					b.Fatalf("scanned %d files, want 4000", len(items))
+				}
+			}
+		})
+	}
+}
+
+func TestTruncatePreview(t *testing.T) {
+	tests := []struct {
+		name      string
+		preview   string
+		lineCut   bool // expect the cut at the end of a line
+		unchanged bool
+	}{
+		{name: "short text is kept", preview: "héllo\nwörld", unchanged: true},
+		{name: "two-byte runes without newlines", preview: strings.Repeat("é", 500)},
+		{name: "four-byte runes without newlines", preview: "xy" + strings.Repeat("🙂", 250)},
+		{name: "lines of three-byte runes", preview: strings.Repeat("日本語のテキスト\n", 60), lineCut: true},
+		{name: "multibyte line straddling the cut", preview: strings.Repeat("a", 698) + "\n" + strings.Repeat("🙂", 50), lineCut: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncatePreview(tt.preview, 800)
+			if !utf8.ValidString(got) {
+				t.Fatalf("result is not valid UTF-8: %q", got[max(len(got)-10, 0):])
+			}
+			if tt.unchanged {
+				if got != tt.preview {
+					t.Fatalf("got %q, want it unchanged", got)
+				}
+				return
+			}
+			if len(got) > 800 {
+				t.Errorf("result is %d bytes, want at most 800", len(got))
+			}
+			if !strings.HasSuffix(got, "...") {
+				t.Errorf("result doesn't end in ...: %q", got[len(got)-10:])
+			}
+			if tt.lineCut {
+				kept, ok := strings.CutSuffix(got, "\n...")
+				if !ok || !strings.HasPrefix(tt.preview, kept+"\n") {
+					t.Errorf("cut doesn't land on a line boundary: %q", got[max(len(got)-20, 0):])
+				}
+			}
+		})
+	}
+}
+
+func TestScanDirectoryFixture(t *testing.T) {
+	root := t.TempDir()
+	if err := fixture.Build(root); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without --recursive the scan reviews exactly the visible top-level
+	// entries, symlinks as themselves
+	entries, err := fs.ReadDir(fixture.FS(), ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), ".") {
+			want = append(want, entry.Name())
+		}
+	}
+
+	items, err := scanDirectory(root, Config{PreviewLines: 15, PreviewMaxSize: 10 << 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, item := range items {
+		got = append(got, item.Name)
+		if wantLink := fixture.FS()[item.Name].Mode&fs.ModeSymlink != 0; item.IsSymlink != wantLink {
+			t.Errorf("%s: IsSymlink = %v, want %v", item.Name, item.IsSymlink, wantLink)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("scanned %v, want %v", got, want)
+	}
+}
+
+func TestScanDirectoryFollowSymlinksLoop(t *testing.T) {
+	root, cleanup, err := fixture.TempDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	// src/lib/loop points back up the tree; it must not be walked forever
+	items, err := scanDirectory(root, Config{Recursive: true, FollowSymlinks: true, PreviewLines: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for _, item := range items {
+		rel, _ := filepath.Rel(root, item.Path)
+		if seen[rel] {
+			t.Errorf("%s scanned twice", rel)
+		}
+		seen[rel] = true
+	}
+	if !seen[filepath.Join("src", "lib", "util.js")] {
+		t.Errorf("src/lib/util.js missing from %v", seen)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Space in review opens the whole file in a scrollable view. The card's
+// preview is only the first few lines; the full file is read when asked for
+// and dropped again when the view closes, so scanning stays fast.
+
+// fullPreviewMaxSize is how much of a file the full preview reads at most.
+const fullPreviewMaxSize = 4 << 20
+
+// openFullPreview reads the current file for the full preview, or leaves a
+// notice when there is nothing sensible to show.
+func (m model) openFullPreview() (tea.Model, tea.Cmd) {
+	file := m.files[m.currentFile]
+	if file.IsDir || file.IsSymlink || m.cfg.Archive != "" {
+		m.notice = "Full preview only works for files on disk"
+		return m, nil
+	}
+
+	f, err := os.Open(file.Path)
+	if err != nil {
+		m.notice = fmt.Sprintf("Could not read file: %v", err)
+		return m, nil
+	}
+	data, err := io.ReadAll(io.LimitReader(f, fullPreviewMaxSize+1))
+	f.Close()
+	if err != nil {
+		m.notice = fmt.Sprintf("Could not read file: %v", err)
+		return m, nil
+	}
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		m.notice = "Binary file; there is no full preview"
+		return m, nil
+	}
+
+	m.fullPreviewCut = len(data) > fullPreviewMaxSize
+	if m.fullPreviewCut {
+		data = data[:fullPreviewMaxSize]
+		if nl := bytes.LastIndexByte(data, '\n'); nl > 0 {
+			data = data[:nl]
+		}
+	}
+	text := strings.ReplaceAll(string(data), "\t", "    ")
+	text = renderPreview(text, file, m.cfg.Theme, m.rawPreview)
+	m.fullPreview = strings.Split(strings.TrimRight(text, "\n"), "\n")
+	m.fullPreviewPath = file.Path
+	m.fullPreviewAt = 0
+	return m, nil
+}
+
+// fullPreviewHeight is how many lines of the file fit between the header
+// and the key hints.
+func (m model) fullPreviewHeight() int {
+	if m.height == 0 {
+		return 20
+	}
+	return max(m.height-5, 3)
+}
+
+func (m model) scrollFullPreview(delta int) model {
+	last := max(len(m.fullPreview)-m.fullPreviewHeight(), 0)
+	m.fullPreviewAt = min(max(m.fullPreviewAt+delta, 0), last)
+	return m
+}
+
+func (m model) handleFullPreviewInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		return m.scrollFullPreview(-1), nil
+	case "down", "j":
+		return m.scrollFullPreview(1), nil
+	case "pgup":
+		return m.scrollFullPreview(-m.fullPreviewHeight()), nil
+	case "pgdown":
+		return m.scrollFullPreview(m.fullPreviewHeight()), nil
+	case "home", "g":
+		return m.scrollFullPreview(-len(m.fullPreview)), nil
+	case "end", "G":
+		return m.scrollFullPreview(len(m.fullPreview)), nil
+	case " ", "esc", "q":
+		m.fullPreview = nil
+		return m, nil
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) fullPreviewView() string {
+	height := m.fullPreviewHeight()
+	end := min(m.fullPreviewAt+height, len(m.fullPreview))
+
+	position := fmt.Sprintf("lines %d-%d of %d", m.fullPreviewAt+1, end, len(m.fullPreview))
+	if m.fullPreviewCut {
+		position += fmt.Sprintf(", only the first %s is shown", formatSize(fullPreviewMaxSize))
+	}
+	header := fmt.Sprintf("%s %s  %s", titleStyle.Render("Preview"),
+		fitPath(m.displayPath(m.fullPreviewPath), 60), disabledHintStyle.Render(position))
+
+	body := strings.Join(m.fullPreview[m.fullPreviewAt:end], "\n")
+	if m.width > 0 {
+		// Long lines are cut rather than wrapped so scrolling stays one
+		// line per step
+		body = lipgloss.NewStyle().MaxWidth(m.width).Render(body)
+	}
+	return fmt.Sprintf("\n%s\n\n%s\n\n↑/↓ scroll | pgup/pgdown page | g/G top/bottom | space/esc=back to review", header, body)
+}
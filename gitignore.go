@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// gitignoreRule is one pattern line of a .gitignore file.
+type gitignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// gitignore matches paths relative to the directory its file was read from.
+// Like git, the last matching rule wins.
+type gitignore struct {
+	rules []gitignoreRule
+}
+
+// loadGitignore reads dir/.gitignore. A missing file yields an empty
+// matcher that ignores nothing.
+func loadGitignore(dir string) (*gitignore, error) {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if os.IsNotExist(err) {
+		return &gitignore{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ignore := &gitignore{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseGitignoreLine(scanner.Text()); ok {
+			ignore.rules = append(ignore.rules, rule)
+		}
+	}
+	return ignore, scanner.Err()
+}
+
+func parseGitignoreLine(line string) (gitignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return gitignoreRule{}, false
+	}
+
+	var rule gitignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return gitignoreRule{}, false
+	}
+
+	// A slash anywhere but the end anchors the pattern to the root;
+	// otherwise it may match at any depth
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expr := globToRegexp(line)
+	if !anchored {
+		expr = "(?:.*/)?" + expr
+	}
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return gitignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// globToRegexp translates gitignore glob syntax, including "**", into a
+// regular expression over slash-separated paths.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// Match reports whether rel, a slash-separated path relative to the
+// .gitignore's directory, is ignored.
+func (g *gitignore) Match(rel string, isDir bool) bool {
+	if g == nil {
+		return false
+	}
+	ignored := false
+	for _, rule := range g.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.re.MatchString(rel) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"os"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// graphicsProtocol is how the terminal can show real images. Thumbnails are
+// drawn with half-block characters when it can't.
+type graphicsProtocol int
+
+const (
+	graphicsNone graphicsProtocol = iota
+	graphicsKitty
+	graphicsITerm
+	graphicsSixel
+)
+
+// graphics is the protocol of the terminal we are drawing on, detected once
+// at startup.
+var graphics = graphicsNone
+
+// Cell size in pixels assumed when the terminal doesn't report one; it only
+// decides how sharp kitty and iTerm2 thumbnails are.
+const (
+	defaultCellWidth  = 10
+	defaultCellHeight = 20
+)
+
+// kittyClear removes every image kitty shows, quietly.
+const kittyClear = "\x1b_Ga=d,d=A,q=2\x1b\\"
+
+// graphicBlank marks the cells of the card an image is drawn over. It is
+// blank on screen, unlike a space it can be found in the rendered view.
+const graphicBlank = "⠀"
+
+func setupGraphics() {
+	if info, err := os.Stdout.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return
+	}
+	graphics = detectGraphics()
+	if graphics == graphicsSixel {
+		// Sixel images are sized in pixels; without knowing the cell size
+		// they'd spill over the card
+		if w, h := cellPixels(); w == 0 || h == 0 {
+			graphics = graphicsNone
+		}
+	}
+}
+
+// detectGraphics picks the image protocol from what terminals announce about
+// themselves in the environment. Multiplexers don't pass images through, so
+// inside tmux or screen there is none.
+func detectGraphics() graphicsProtocol {
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	if os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux") {
+		return graphicsNone
+	}
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", program == "ghostty":
+		return graphicsKitty
+	case program == "iTerm.app", program == "WezTerm", os.Getenv("LC_TERMINAL") == "iTerm2":
+		return graphicsITerm
+	case strings.HasPrefix(term, "foot"), term == "mlterm", strings.HasPrefix(term, "yaft"), strings.Contains(term, "sixel"):
+		return graphicsSixel
+	}
+	return graphicsNone
+}
+
+// encodeGraphic encodes img for the detected protocol, to be drawn over
+// cols×rows cells. It returns "" when there is no protocol.
+func encodeGraphic(img image.Image, cols, rows int) string {
+	cellW, cellH := cellPixels()
+	if cellW == 0 || cellH == 0 {
+		cellW, cellH = defaultCellWidth, defaultCellHeight
+	}
+	pixels := downscale(img, cols*cellW, rows*cellH)
+	if len(pixels) == 0 {
+		return ""
+	}
+	small := image.NewRGBA(image.Rect(0, 0, len(pixels[0]), len(pixels)))
+	for y, row := range pixels {
+		for x, c := range row {
+			small.SetRGBA(x, y, c)
+		}
+	}
+
+	switch graphics {
+	case graphicsKitty:
+		return kittyImage(small, cols, rows)
+	case graphicsITerm:
+		return itermImage(small, cols, rows)
+	case graphicsSixel:
+		return sixelImage(small)
+	}
+	return ""
+}
+
+// kittyImage sends img as PNG in chunks, the most kitty accepts at once,
+// replacing whatever image was shown before.
+func kittyImage(img image.Image, cols, rows int) string {
+	var data bytes.Buffer
+	if png.Encode(&data, img) != nil {
+		return ""
+	}
+	payload := base64.StdEncoding.EncodeToString(data.Bytes())
+
+	var b strings.Builder
+	b.WriteString(kittyClear)
+	for first := true; first || payload != ""; first = false {
+		chunk := payload[:min(len(payload), 4096)]
+		payload = payload[len(chunk):]
+		more := 0
+		if payload != "" {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gq=2,m=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return b.String()
+}
+
+func itermImage(img image.Image, cols, rows int) string {
+	var data bytes.Buffer
+	if png.Encode(&data, img) != nil {
+		return ""
+	}
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+		data.Len(), cols, rows, base64.StdEncoding.EncodeToString(data.Bytes()))
+}
+
+// sixelImage draws img with the 216 web-safe colors, one band of six pixel
+// rows at a time and one pass over the band per color in it.
+func sixelImage(img image.Image) string {
+	bounds := img.Bounds()
+	pal := image.NewPaletted(bounds, palette.WebSafe)
+	draw.FloydSteinberg.Draw(pal, bounds, img, bounds.Min)
+	w, h := bounds.Dx(), bounds.Dy()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\x1bPq\"1;1;%d;%d", w, h)
+	for i, c := range palette.WebSafe {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+	for y0 := 0; y0 < h; y0 += 6 {
+		var used []uint8
+		for y := y0; y < min(y0+6, h); y++ {
+			for x := range w {
+				if i := pal.ColorIndexAt(x, y); !slices.Contains(used, i) {
+					used = append(used, i)
+				}
+			}
+		}
+		for n, i := range used {
+			if n > 0 {
+				b.WriteByte('$')
+			}
+			fmt.Fprintf(&b, "#%d", i)
+			var last byte
+			run := 0
+			flush := func() {
+				switch {
+				case run > 3:
+					fmt.Fprintf(&b, "!%d%c", run, last)
+				case run > 0:
+					b.WriteString(strings.Repeat(string(last), run))
+				}
+			}
+			for x := range w {
+				bits := 0
+				for k := range 6 {
+					if y0+k < h && pal.ColorIndexAt(x, y0+k) == i {
+						bits |= 1 << k
+					}
+				}
+				if c := byte(63 + bits); c == last {
+					run++
+				} else {
+					flush()
+					last, run = c, 1
+				}
+			}
+			flush()
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// graphicPlaceholder keeps the shape of the half-block thumbnail text but
+// leaves its cells blank for the real image.
+func graphicPlaceholder(thumbnail string) string {
+	lines := strings.Split(thumbnail, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Repeat(graphicBlank, lipgloss.Width(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// graphicSpot finds the placeholder in the lines of a rendered view: its
+// first and last line and the column it starts at.
+func graphicSpot(lines []string) (top, bottom, col int, ok bool) {
+	top = -1
+	for i, line := range lines {
+		at := strings.Index(line, graphicBlank)
+		if at < 0 {
+			if top >= 0 {
+				break
+			}
+			continue
+		}
+		if top < 0 {
+			top, col = i, lipgloss.Width(line[:at])
+		}
+		bottom = i
+	}
+	return top, bottom, col, top >= 0
+}
+
+// graphicPlacement is the image drawn on screen and the screen lines it was
+// drawn into.
+type graphicPlacement struct {
+	path  string
+	lines string
+}
+
+// placeGraphic draws the current card's image into its placeholder. Image
+// sequences are far longer than a line, which bubbletea's renderer would
+// cut, so the lines around the placeholder are handed to the renderer as a
+// scroll area: it writes them untouched and leaves them alone afterwards.
+// It runs after every update and only acts when what is on screen changes.
+func (m model) placeGraphic(cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	if graphics == graphicsNone {
+		return m, cmd
+	}
+
+	var want graphicPlacement
+	var sync tea.Cmd
+	if m.screen == ScreenReview && !m.showHelp && !m.rawPreview && m.currentFile >= 0 && m.currentFile < len(m.files) {
+		file := m.files[m.currentFile]
+		lines := strings.Split(m.view(), "\n")
+		if m.height > 0 && len(lines) > m.height {
+			// The renderer drops what doesn't fit from the top
+			lines = lines[len(lines)-m.height:]
+		}
+		if top, bottom, col, ok := graphicSpot(lines); ok && file.Graphic != "" && top > 0 && bottom+1 < len(lines) {
+			// One line either side, so nothing the image sequence does
+			// with the cursor can scroll the card
+			region := slices.Clone(lines[top-1 : bottom+2])
+			want = graphicPlacement{path: file.Path, lines: strings.Join(region, "\n")}
+			region[len(region)-1] += fmt.Sprintf("\x1b[%d;%dH", top+1, col+1) + file.Graphic
+			sync = tea.SyncScrollArea(region, top, bottom+2)
+		}
+	}
+
+	switch {
+	case want == m.placed:
+		return m, cmd
+	case want.path == "":
+		sync = tea.ClearScrollArea
+	}
+	m.placed = want
+	return m, tea.Batch(cmd, sync)
+}
+
+// graphicSequence is what the view sends for images: with kitty, whose
+// images stay on screen until removed, a clear whenever none is placed. The
+// renderer holds back an escape sequence until it sees a letter end it, so
+// a no-op color reset follows to let the clear's terminator through.
+func (m model) graphicSequence() string {
+	if graphics == graphicsKitty && m.placed.path == "" {
+		return kittyClear + "\x1b[m"
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestDetectGraphics(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want graphicsProtocol
+	}{
+		{"plain xterm", map[string]string{"TERM": "xterm-256color"}, graphicsNone},
+		{"kitty", map[string]string{"TERM": "xterm-kitty", "KITTY_WINDOW_ID": "1"}, graphicsKitty},
+		{"ghostty", map[string]string{"TERM_PROGRAM": "ghostty"}, graphicsKitty},
+		{"iterm", map[string]string{"TERM_PROGRAM": "iTerm.app"}, graphicsITerm},
+		{"iterm over ssh", map[string]string{"LC_TERMINAL": "iTerm2"}, graphicsITerm},
+		{"foot", map[string]string{"TERM": "foot"}, graphicsSixel},
+		{"kitty inside tmux", map[string]string{"TERM": "tmux-256color", "KITTY_WINDOW_ID": "1", "TMUX": "/tmp/tmux"}, graphicsNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"TERM", "TERM_PROGRAM", "KITTY_WINDOW_ID", "LC_TERMINAL", "TMUX"} {
+				t.Setenv(key, tt.env[key])
+			}
+			if got := detectGraphics(); got != tt.want {
+				t.Errorf("detectGraphics() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphicSpot(t *testing.T) {
+	placeholder := strings.Split(graphicPlaceholder("▀▀▀\n▀▀▀"), "\n")
+	lines := []string{
+		"",
+		"╭──────╮",
+		"│ \x1b[1mx\x1b[0m " + placeholder[0] + " │",
+		"│   " + placeholder[1] + " │",
+		"╰──────╯",
+	}
+	top, bottom, col, ok := graphicSpot(lines)
+	if !ok || top != 2 || bottom != 3 || col != 4 {
+		t.Errorf("graphicSpot = %d, %d, %d, %v; want 2, 3, 4, true", top, bottom, col, ok)
+	}
+	if _, _, _, ok := graphicSpot(lines[4:]); ok {
+		t.Errorf("graphicSpot found a placeholder in a view without one")
+	}
+}
+
+func TestKittyImageChunks(t *testing.T) {
+	// Noise doesn't compress, so the PNG needs several chunks
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	rng := rand.New(rand.NewSource(1))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rng.Intn(256))
+	}
+	seq := kittyImage(img, 8, 4)
+
+	if !strings.HasPrefix(seq, kittyClear+"\x1b_Ga=T,f=100,q=2,C=1,c=8,r=4,m=1;") {
+		t.Fatalf("sequence starts with %q", seq[:min(len(seq), 80)])
+	}
+	chunks := strings.Split(strings.TrimPrefix(seq, kittyClear), "\x1b\\")
+	chunks = chunks[:len(chunks)-1]
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want several", len(chunks))
+	}
+	for i, chunk := range chunks {
+		payload := chunk[strings.IndexByte(chunk, ';')+1:]
+		if len(payload) > 4096 {
+			t.Errorf("chunk %d carries %d bytes", i, len(payload))
+		}
+		last := i == len(chunks)-1
+		if strings.Contains(chunk, ",m=0;") != last {
+			t.Errorf("chunk %d: m=0 should mark only the last chunk", i)
+		}
+	}
+}
+
+func TestSixelImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 13))
+	for y := range 13 {
+		for x := range 20 {
+			img.Set(x, y, color.RGBA{0xff, 0, 0, 0xff})
+		}
+	}
+	seq := sixelImage(img)
+	if !strings.HasPrefix(seq, "\x1bPq\"1;1;20;13") || !strings.HasSuffix(seq, "\x1b\\") {
+		t.Fatalf("not a sixel sequence: %q", seq[:min(len(seq), 40)])
+	}
+	// 13 rows of pixels make three bands of six
+	if bands := strings.Count(seq, "-"); bands != 3 {
+		t.Errorf("got %d bands, want 3", bands)
+	}
+	// A solid band is one run per band: full for the first two, the last
+	// one only has its top pixel row
+	if !strings.Contains(seq, "!20~") || !strings.Contains(seq, "!20@") {
+		t.Errorf("solid color not run-length encoded: %q", seq)
+	}
+}
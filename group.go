@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Categories in the order --group reviews them.
+var categoryOrder = []string{"folders", "images", "videos", "audio", "documents", "archives", "packages", "code", "other"}
+
+// itemCategory is fileCategory for a scanned item; directories are reviewed
+// as a category of their own.
+func itemCategory(file FileItem) string {
+	if file.IsDir && !file.IsSymlink {
+		return "folders"
+	}
+	return fileCategory(file.Path)
+}
+
+// groupByCategory stably reorders files by category, keeping any previous
+// ordering within each category.
+func groupByCategory(files []FileItem) {
+	rank := make(map[string]int, len(categoryOrder))
+	for i, category := range categoryOrder {
+		rank[category] = i
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return rank[itemCategory(files[i])] < rank[itemCategory(files[j])]
+	})
+}
+
+// groupHeader reports whether the current file opens a category whose
+// header card has not been shown yet.
+func (m model) groupHeader() (string, bool) {
+	if !m.cfg.Group || m.currentFile >= len(m.files) {
+		return "", false
+	}
+	category := itemCategory(m.files[m.currentFile])
+	return category, !m.seenGroups[category]
+}
+
+// groupFiles lists the indexes of the undecided files in category from the
+// current file onward.
+func (m model) groupFiles(category string) []int {
+	var indexes []int
+	for i := m.currentFile; i < len(m.files); i++ {
+		file := m.files[i]
+		if !file.Decided && !file.Skipped && itemCategory(file) == category {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// handleGroupInput answers a category header: keep or delete the whole
+// category at once, or review its files one by one.
+func (m model) handleGroupInput(category string, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.cfg.Keys.action(msg.String()) {
+	case actionQuit:
+		return m, tea.Quit
+	case actionKeep, actionDelete:
+		keep := m.cfg.Keys.action(msg.String()) == actionKeep
+		m.beginUndo()
+		for _, i := range m.groupFiles(category) {
+			if keep || m.canDelete(m.files[i]) {
+				m.remember(i)
+				m.decide(i, keep)
+			}
+		}
+		m.seenGroups[category] = true
+		m.currentFile--
+		return m.nextFile()
+	}
+	m.seenGroups[category] = true
+	return m, nil
+}
+
+func (m model) groupHeaderView(category string) string {
+	indexes := m.groupFiles(category)
+	size := int64(0)
+	for _, i := range indexes {
+		size += m.files[i].Size
+	}
+
+	icon := itemIcon(m.files[m.currentFile])
+	title := strings.ToUpper(category[:1]) + category[1:]
+	card := m.cardStyle(false).Render(fmt.Sprintf("%s  %s\n\n%d files, %s", icon, title, len(indexes), formatSize(size)))
+	return fmt.Sprintf("\n%s\n\n%s\n\n%s=keep all | %s=delete all | any other key=review one by one | %s=quit",
+		titleStyle.Render("File Review"), card,
+		m.cfg.Keys.label(actionKeep), m.cfg.Keys.label(actionDelete), m.cfg.Keys.label(actionQuit))
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// sensitiveDirs are system directories that are never a sensible target for
+// a cleanup, in addition to filesystem roots and the home directory.
+var sensitiveDirs = map[string][]string{
+	"linux":   {"/bin", "/boot", "/dev", "/etc", "/home", "/lib", "/lib64", "/mnt", "/opt", "/proc", "/root", "/sbin", "/srv", "/sys", "/usr", "/var"},
+	"darwin":  {"/Applications", "/Library", "/System", "/Users", "/bin", "/etc", "/private", "/sbin", "/usr", "/var"},
+	"windows": {`C:\Program Files`, `C:\Program Files (x86)`, `C:\ProgramData`, `C:\Users`, `C:\Windows`},
+}
+
+// dangerousDir explains why dir is too risky to review without --force, or
+// returns "" when it is fine.
+func dangerousDir(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+
+	if filepath.Dir(abs) == abs {
+		return "it is a filesystem root"
+	}
+	if home, err := os.UserHomeDir(); err == nil && samePath(abs, home) {
+		return "it is your home directory"
+	}
+	for _, sensitive := range sensitiveDirs[runtime.GOOS] {
+		if samePath(abs, sensitive) {
+			return "it is a system directory"
+		}
+	}
+	return ""
+}
+
+// checkTargets refuses to review anything dangerousDir flags, unless --force
+// is set or the run can't delete anything. With --stdin the targets are the
+// paths that were read, which are deleted like any other item.
+func checkTargets(cfg Config) error {
+	if cfg.Force || cfg.Audit || cfg.DryRun || cfg.ListOnly || cfg.Archive != "" {
+		return nil
+	}
+	targets := cfg.Dirs
+	if cfg.Stdin {
+		targets = cfg.Paths
+	}
+	// One careless delete-all here would be catastrophic
+	for _, target := range targets {
+		if reason := dangerousDir(target); reason != "" {
+			return fmt.Errorf("refusing to review %s because %s; pass --force if you really mean it", target, reason)
+		}
+	}
+	return nil
+}
+
+func samePath(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Clean(a), filepath.Clean(b))
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// hashCacheLimit caps the number of remembered hashes; the least recently
+// used entries are dropped first when saving.
+const hashCacheLimit = 100000
+
+type hashCacheEntry struct {
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"`
+	Hash     string    `json:"hash"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// hashCache remembers content hashes between runs, keyed by absolute path
+// and validated against size and modification time, so repeated duplicate
+// scans only rehash files that actually changed.
+type hashCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]hashCacheEntry
+	dirty   bool
+}
+
+func hashCachePath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "hashes.json"), nil
+}
+
+// loadHashCache reads the cache from disk. A missing or unreadable cache is
+// not an error; it simply starts empty.
+func loadHashCache() *hashCache {
+	cache := &hashCache{entries: make(map[string]hashCacheEntry)}
+
+	path, err := hashCachePath()
+	if err != nil {
+		return cache
+	}
+	cache.path = path
+
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &cache.entries)
+	}
+	return cache
+}
+
+// Hash returns the SHA-256 of the file at path, reusing the cached value when
+// the file's size and modification time are unchanged.
+func (c *hashCache) Hash(path string, size int64, modTime time.Time) (string, error) {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		key = path
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && entry.Size == size && entry.ModTime.Equal(modTime) {
+		c.mu.Lock()
+		entry.LastUsed = time.Now()
+		c.entries[key] = entry
+		c.dirty = true
+		c.mu.Unlock()
+		return entry.Hash, nil
+	}
+
+	hash, err := hashFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = hashCacheEntry{Size: size, ModTime: modTime, Hash: hash, LastUsed: time.Now()}
+	c.dirty = true
+	c.mu.Unlock()
+
+	return hash, nil
+}
+
+// Save writes the cache back to disk, trimming it to hashCacheLimit entries.
+func (c *hashCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty || c.path == "" {
+		return nil
+	}
+
+	if len(c.entries) > hashCacheLimit {
+		keys := make([]string, 0, len(c.entries))
+		for key := range c.entries {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return c.entries[keys[i]].LastUsed.After(c.entries[keys[j]].LastUsed)
+		})
+		for _, key := range keys[hashCacheLimit:] {
+			delete(c.entries, key)
+		}
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHashCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+	t.Setenv("HOME", cacheDir)
+
+	path := filepath.Join(t.TempDir(), "a.bin")
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sum := func(content string) string {
+		s := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(s[:])
+	}
+
+	write("first")
+	cache := loadHashCache()
+	hash, err := cache.Hash(path, 5, modTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != sum("first") {
+		t.Fatalf("Hash = %s, want the SHA-256 of the content", hash)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same size and modification time: the saved hash is trusted without
+	// reading the file again
+	write("other")
+	cache = loadHashCache()
+	if hash, _ := cache.Hash(path, 5, modTime); hash != sum("first") {
+		t.Errorf("unchanged file was rehashed after reloading the cache")
+	}
+
+	// A newer modification time means the file changed
+	later := modTime.Add(time.Minute)
+	if hash, _ := cache.Hash(path, 5, later); hash != sum("other") {
+		t.Errorf("changed file got hash %s, want %s", hash, sum("other"))
+	}
+
+	os.Remove(path)
+	if _, err := cache.Hash(path, 5, modTime.Add(time.Hour)); err == nil {
+		t.Errorf("hashing a missing file succeeded")
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type helpEntry struct {
+	keys   string
+	action string
+}
+
+// helpEntries lists the keys that work on the current screen. Rebindable
+// actions are labelled from the active keymap so the overlay always matches
+// what the keys really do.
+func (m model) helpEntries() []helpEntry {
+	keys := m.cfg.Keys
+	switch m.screen {
+	case ScreenReview:
+		entries := []helpEntry{
+			{keys.label(actionKeep), "keep file"},
+			{keys.label(actionDelete), "mark file for deletion"},
+			{keys.label(actionSkip), "skip file, review it later"},
+		}
+		if m.cfg.MoveTo != "" && m.cfg.Archive == "" {
+			entries = append(entries,
+				helpEntry{"m", "keep and move into " + m.cfg.MoveTo},
+				helpEntry{"M", "move and leave a symlink behind"})
+		}
+		return append(entries,
+			helpEntry{"D", "delete all remaining files, then confirm"},
+			helpEntry{"K", "keep all remaining files, then confirm"},
+			helpEntry{"P", "keep and never ask about this file again"},
+			helpEntry{"o", "keep this copy and delete its duplicates (--find-dupes)"},
+			helpEntry{keys.label(actionUndo), "undo the last decision (repeatable)"},
+			helpEntry{"ctrl+r", "redo what was just undone"},
+			helpEntry{"p", "preview a file that was too large"},
+			helpEntry{"+/-", "more or fewer preview lines"},
+			helpEntry{"space", "show the whole file in a scrollable view"},
+			helpEntry{"v", "toggle raw and rendered preview"},
+			helpEntry{"e", "open in $EDITOR or $PAGER (default less)"},
+			helpEntry{"z", "focus on the current folder / back to full queue"},
+			helpEntry{"/", "search the queue by path"},
+			helpEntry{"esc", "end search"},
+			helpEntry{keys.label(actionQuit), "quit"},
+		)
+	case ScreenList:
+		return []helpEntry{
+			{"↑/↓", "move (pgup/pgdn by page, g/G to the ends)"},
+			{"space", "select or unselect the file"},
+			{"a", "select all / clear the selection"},
+			{keys.label(actionDelete), "mark the selection (or the file) for deletion"},
+			{keys.label(actionKeep), "keep the selection (or the file)"},
+			{"x", "clear the decision"},
+			{"enter", "go to confirmation"},
+			{keys.label(actionQuit), "quit"},
+		}
+	case ScreenConfirm:
+		if m.confirmEach {
+			return []helpEntry{
+				{"y", "delete this file"},
+				{"n", "keep this file after all"},
+				{"esc", "back to the list"},
+				{"q", "quit without deleting"},
+			}
+		}
+		entries := []helpEntry{
+			{"enter", "confirm"},
+			{"↑/↓", "scroll the list (pgup/pgdn by page)"},
+			{"r", "review files that arrived while watching"},
+			{"n", "cancel and quit"},
+			{"q", "quit, offering to save your decisions first"},
+		}
+		if m.cfg.List {
+			entries = append(entries, helpEntry{"esc", "back to the file list"})
+		}
+		return entries
+	case ScreenEmptyDirs:
+		return []helpEntry{
+			{"enter", "remove the empty folders"},
+			{"n/q", "leave them"},
+		}
+	case ScreenComplete:
+		return []helpEntry{
+			{"u", "restore files moved to the trash"},
+			{"q", "quit"},
+		}
+	}
+	return []helpEntry{{"ctrl+c", "quit"}}
+}
+
+func (m model) helpView() string {
+	entries := append(m.helpEntries(), helpEntry{"?/esc", "close help"})
+	width := 0
+	for _, entry := range entries {
+		width = max(width, len([]rune(entry.keys)))
+	}
+
+	var b strings.Builder
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "  %-*s  %s\n", width, entry.keys, entry.action)
+	}
+	return fmt.Sprintf("\n%s\n\n%s", titleStyle.Render("Help"), b.String())
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// fileIcons maps lower-case extensions and file names to the icon shown for
+// them. The special names folder, symlink and file are the icons for
+// directories, links and anything not listed. The defaults can be changed
+// in the [icons] table of the config file, applied once at startup by
+// setupIcons.
+var fileIcons = map[string]string{
+	"folder":  "📁",
+	"symlink": "🔗",
+	"file":    "📄",
+
+	// Code files
+	".go":    "🐹",
+	".js":    "🟨",
+	".ts":    "🔷",
+	".py":    "🐍",
+	".java":  "☕",
+	".c":     "🔧",
+	".cpp":   "🔧",
+	".h":     "📋",
+	".rs":    "🦀",
+	".php":   "🐘",
+	".rb":    "💎",
+	".swift": "🍎",
+	".kt":    "🟣",
+	".scala": "🔴",
+
+	// Web files
+	".html": "🌐",
+	".css":  "🎨",
+	".scss": "🎨",
+	".sass": "🎨",
+	".jsx":  "⚛️",
+	".tsx":  "⚛️",
+	".vue":  "💚",
+
+	// Data files
+	".json": "📋",
+	".xml":  "📋",
+	".yaml": "📋",
+	".yml":  "📋",
+	".toml": "📋",
+	".ini":  "⚙️",
+	".cfg":  "⚙️",
+	".conf": "⚙️",
+
+	// Documents
+	".md":   "📝",
+	".txt":  "📄",
+	".pdf":  "📕",
+	".doc":  "📘",
+	".docx": "📘",
+	".xls":  "📗",
+	".xlsx": "📗",
+	".ppt":  "📙",
+	".pptx": "📙",
+
+	// Images
+	".jpg":  "🖼️",
+	".jpeg": "🖼️",
+	".png":  "🖼️",
+	".gif":  "🖼️",
+	".svg":  "🎨",
+	".ico":  "🖼️",
+	".webp": "🖼️",
+	".bmp":  "🖼️",
+
+	// Audio
+	".mp3":  "🎵",
+	".wav":  "🎵",
+	".flac": "🎵",
+	".m4a":  "🎵",
+	".ogg":  "🎵",
+
+	// Video
+	".mp4":  "🎬",
+	".avi":  "🎬",
+	".mkv":  "🎬",
+	".mov":  "🎬",
+	".wmv":  "🎬",
+	".flv":  "🎬",
+	".webm": "🎬",
+
+	// Archives
+	".zip": "📦",
+	".tar": "📦",
+	".gz":  "📦",
+	".rar": "📦",
+	".7z":  "📦",
+	".bz2": "📦",
+	".xz":  "📦",
+
+	// Executables
+	".exe": "⚡",
+	".app": "📱",
+	".deb": "📦",
+	".rpm": "📦",
+	".dmg": "💿",
+	".iso": "💿",
+
+	// System files
+	".log":   "📋",
+	".tmp":   "🗑️",
+	".cache": "🗑️",
+	".bak":   "💾",
+	".old":   "💾",
+
+	// Shell scripts
+	".sh":   "🐚",
+	".bash": "🐚",
+	".zsh":  "🐚",
+	".fish": "🐚",
+	".bat":  "🖥️",
+	".ps1":  "🔷",
+
+	// Database
+	".db":     "🗄️",
+	".sqlite": "🗄️",
+	".sql":    "🗄️",
+
+	// Git
+	".git": "🔀",
+
+	// Docker
+	"dockerfile": "🐳",
+}
+
+// asciiIcons replace fileIcons with --ascii, for terminals that draw emoji
+// as double-width glyphs or empty boxes. Every marker is three cells wide.
+var asciiIcons = map[string]string{
+	"folder":  "[D]",
+	"symlink": "[L]",
+	"file":    "[F]",
+
+	// Code files
+	".go":    "[G]",
+	".js":    "[J]",
+	".jsx":   "[J]",
+	".ts":    "[T]",
+	".tsx":   "[T]",
+	".py":    "[P]",
+	".java":  "[J]",
+	".c":     "[C]",
+	".cpp":   "[C]",
+	".h":     "[C]",
+	".rs":    "[R]",
+	".php":   "[P]",
+	".rb":    "[R]",
+	".swift": "[S]",
+	".kt":    "[K]",
+	".scala": "[S]",
+
+	// Web files
+	".html": "[W]",
+	".css":  "[W]",
+	".scss": "[W]",
+	".sass": "[W]",
+	".vue":  "[W]",
+
+	// Data and config files
+	".json": "[=]",
+	".xml":  "[=]",
+	".yaml": "[=]",
+	".yml":  "[=]",
+	".toml": "[=]",
+	".ini":  "[=]",
+	".cfg":  "[=]",
+	".conf": "[=]",
+
+	// Documents
+	".md":   "[M]",
+	".txt":  "[F]",
+	".pdf":  "[O]",
+	".doc":  "[O]",
+	".docx": "[O]",
+	".xls":  "[O]",
+	".xlsx": "[O]",
+	".ppt":  "[O]",
+	".pptx": "[O]",
+
+	// Media
+	".jpg":  "[I]",
+	".jpeg": "[I]",
+	".png":  "[I]",
+	".gif":  "[I]",
+	".svg":  "[I]",
+	".ico":  "[I]",
+	".webp": "[I]",
+	".bmp":  "[I]",
+	".mp3":  "[A]",
+	".wav":  "[A]",
+	".flac": "[A]",
+	".m4a":  "[A]",
+	".ogg":  "[A]",
+	".mp4":  "[V]",
+	".avi":  "[V]",
+	".mkv":  "[V]",
+	".mov":  "[V]",
+	".wmv":  "[V]",
+	".flv":  "[V]",
+	".webm": "[V]",
+
+	// Archives and packages
+	".zip": "[Z]",
+	".tar": "[Z]",
+	".gz":  "[Z]",
+	".rar": "[Z]",
+	".7z":  "[Z]",
+	".bz2": "[Z]",
+	".xz":  "[Z]",
+	".deb": "[Z]",
+	".rpm": "[Z]",
+	".dmg": "[Z]",
+	".iso": "[Z]",
+
+	// Executables and scripts
+	".exe":  "[X]",
+	".app":  "[X]",
+	".sh":   "[$]",
+	".bash": "[$]",
+	".zsh":  "[$]",
+	".fish": "[$]",
+	".bat":  "[$]",
+	".ps1":  "[$]",
+
+	// Leftovers
+	".log":   "[~]",
+	".tmp":   "[~]",
+	".cache": "[~]",
+	".bak":   "[~]",
+	".old":   "[~]",
+
+	// Databases
+	".db":     "[B]",
+	".sqlite": "[B]",
+	".sql":    "[B]",
+}
+
+// iconKinds are the fileIcons entries that aren't file names.
+var iconKinds = []string{"folder", "symlink", "file"}
+
+// itemIcon is the icon shown for a scanned item; symlinks get their own so
+// they are never mistaken for what they point to.
+func itemIcon(file FileItem) string {
+	if file.IsSymlink {
+		return normalizeIcon(fileIcons["symlink"])
+	}
+	return getFileIcon(file.Path, file.IsDir)
+}
+
+func getFileIcon(path string, isDir bool) string {
+	if isDir {
+		return normalizeIcon(fileIcons["folder"])
+	}
+
+	// Names without an extension, like Dockerfile, are matched whole
+	filename := strings.ToLower(filepath.Base(path))
+	if !slices.Contains(iconKinds, filename) {
+		if icon, exists := fileIcons[filename]; exists {
+			return normalizeIcon(icon)
+		}
+	}
+	if icon, exists := fileIcons[strings.ToLower(filepath.Ext(path))]; exists {
+		return normalizeIcon(icon)
+	}
+	return normalizeIcon(fileIcons["file"])
+}
+
+// loadIcons reads the [icons] table of the config file at path, mapping
+// extensions or file names to the icons to use instead of the defaults:
+//
+//	[icons]
+//	".go" = "\ue627"
+//	"dockerfile" = "\uf308"
+//	folder = "\uf07b"
+//
+// A missing file means no overrides.
+func loadIcons(path string) (map[string]string, error) {
+	icons := map[string]string{}
+	err := readConfigSection(path, "icons", func(n int, name, value string) error {
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		icon, err := strconv.Unquote(value)
+		if name == "" || err != nil || icon == "" {
+			return fmt.Errorf("%s:%d: expected name = \"icon\"", path, n)
+		}
+		icons[strings.ToLower(name)] = icon
+		return nil
+	})
+	return icons, err
+}
+
+// setupIcons picks the emoji or, with ascii, the plain marker icons, and
+// puts the icons from the config file over them.
+func setupIcons(ascii bool, icons map[string]string) {
+	if ascii {
+		fileIcons = asciiIcons
+	}
+	for name, icon := range icons {
+		fileIcons[name] = icon
+	}
+}
+
+// plainTerminal guesses from the environment that the terminal can't show
+// emoji: a locale that isn't UTF-8, or the Linux console.
+func plainTerminal() bool {
+	if term := os.Getenv("TERM"); term == "linux" || term == "dumb" {
+		return true
+	}
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(key); locale != "" {
+			locale = strings.ToLower(locale)
+			return !strings.Contains(locale, "utf-8") && !strings.Contains(locale, "utf8")
+		}
+	}
+	// No locale at all says nothing, e.g. on Windows
+	return false
+}
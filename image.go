@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Largest thumbnail drawn on the review card, in terminal cells.
+const (
+	thumbnailCols = 48
+	thumbnailRows = 12
+)
+
+// maxImagePixels is the largest image, by pixel count, that is decoded for
+// a thumbnail. File size says little here: a small, well compressed PNG
+// can decode to gigabytes.
+const maxImagePixels = 40_000_000
+
+var imageExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true,
+}
+
+func isImageFile(path string) bool {
+	return imageExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// imagePreview decodes the image at path and draws a small thumbnail of it
+// with half-block characters: every cell shows two pixels, the upper one as
+// foreground and the lower one as background color. Without color support
+// it falls back to a brightness ramp so the shape is still recognizable.
+//
+// On terminals with an image protocol, graphic is the same thumbnail as a
+// real image covering the cells of the half-block one (see placeGraphic).
+// info describes the image in one line, e.g. "PNG image, 640×480 pixels";
+// the raw preview shows it instead of the thumbnail.
+func imagePreview(path string) (thumbnail, info, graphic string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", ""
+	}
+	defer f.Close()
+
+	config, format, err := image.DecodeConfig(f)
+	if err != nil {
+		return "", "", ""
+	}
+	info = fmt.Sprintf("%s image, %d×%d pixels", strings.ToUpper(format), config.Width, config.Height)
+	if config.Width*config.Height > maxImagePixels {
+		return info + ", too large for a thumbnail", info, ""
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", info, ""
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return "", info, ""
+	}
+	pixels := downscale(img, thumbnailCols, thumbnailRows*2)
+	if len(pixels) == 0 {
+		return "", info, ""
+	}
+	if graphics != graphicsNone {
+		graphic = encodeGraphic(img, len(pixels[0]), (len(pixels)+1)/2)
+	}
+
+	var b strings.Builder
+	for y := 0; y < len(pixels); y += 2 {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		for x := range pixels[y] {
+			top := pixels[y][x]
+			bottom := top
+			if y+1 < len(pixels) {
+				bottom = pixels[y+1][x]
+			}
+
+			if colorProfile == termenv.Ascii {
+				b.WriteByte(brightnessChar(top, bottom))
+				continue
+			}
+			b.WriteString(lipgloss.NewStyle().
+				Foreground(lipgloss.Color(hexColor(top))).
+				Background(lipgloss.Color(hexColor(bottom))).
+				Render("▀"))
+		}
+	}
+	return b.String(), info, graphic
+}
+
+// thumbnailMsg carries a thumbnail decoded in the background.
+type thumbnailMsg struct {
+	path    string
+	preview string
+	info    string
+	graphic string
+}
+
+// loadThumbnail decodes the image at path off the UI goroutine; big photos
+// take long enough to stall key presses otherwise.
+func loadThumbnail(path string) tea.Cmd {
+	return func() tea.Msg {
+		preview, info, graphic := imagePreview(path)
+		return thumbnailMsg{path: path, preview: preview, info: info, graphic: graphic}
+	}
+}
+
+// requestThumbnail starts decoding the thumbnail of the image on screen,
+// once per file. It runs after every update, so every way of arriving at a
+// file is covered.
+func (m model) requestThumbnail(cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	if m.screen != ScreenReview || m.currentFile < 0 || m.currentFile >= len(m.files) {
+		return m, cmd
+	}
+	if m.cfg.Archive != "" || m.cfg.NoPreview {
+		return m, cmd
+	}
+	// Thumbnails are only decoded once a picture is actually looked at;
+	// doing it for every image during the scan would be slow
+	file := m.files[m.currentFile]
+	if !isImageFile(file.Path) || file.IsDir || file.IsSymlink || file.Preview != "" || file.Size > previewHardCap {
+		return m, cmd
+	}
+	if m.thumbnails[file.Path] {
+		return m, cmd
+	}
+	if m.thumbnails == nil {
+		m.thumbnails = make(map[string]bool)
+	}
+	m.thumbnails[file.Path] = true
+	return m, tea.Batch(cmd, loadThumbnail(file.Path))
+}
+
+func (m model) handleThumbnail(msg thumbnailMsg) (tea.Model, tea.Cmd) {
+	if i := m.indexOfFile(msg.path); i >= 0 && m.files[i].Preview == "" {
+		m.files[i].Preview = msg.preview
+		m.files[i].ImageInfo = msg.info
+		m.files[i].Graphic = msg.graphic
+	}
+	return m, nil
+}
+
+// downscale shrinks img to fit within cols×rows pixels, keeping its aspect
+// ratio, by averaging the source pixels that fall into each target pixel.
+func downscale(img image.Image, cols, rows int) [][]color.RGBA {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return nil
+	}
+
+	scale := min(float64(cols)/float64(w), float64(rows)/float64(h), 1)
+	outW := max(1, int(float64(w)*scale))
+	outH := max(1, int(float64(h)*scale))
+
+	pixels := make([][]color.RGBA, outH)
+	for y := range pixels {
+		pixels[y] = make([]color.RGBA, outW)
+		y0, y1 := bounds.Min.Y+y*h/outH, bounds.Min.Y+max((y+1)*h/outH, y*h/outH+1)
+		for x := range pixels[y] {
+			x0, x1 := bounds.Min.X+x*w/outW, bounds.Min.X+max((x+1)*w/outW, x*w/outW+1)
+
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, _ := img.At(sx, sy).RGBA()
+					r, g, b, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), n+1
+				}
+			}
+			pixels[y][x] = color.RGBA{uint8(r / n >> 8), uint8(g / n >> 8), uint8(b / n >> 8), 0xff}
+		}
+	}
+	return pixels
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+const brightnessRamp = " .:-=+*#%@"
+
+func brightnessChar(top, bottom color.RGBA) byte {
+	luma := func(c color.RGBA) float64 {
+		return 0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)
+	}
+	level := (luma(top) + luma(bottom)) / 2 / 256
+	return brightnessRamp[int(level*float64(len(brightnessRamp)))]
+}
@@ -0,0 +1,166 @@
+// Package fixture builds a reproducible directory tree for exercising the
+// scanner and deletion code. It is only meant to be imported from tests.
+package fixture
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing/fstest"
+	"time"
+)
+
+// BaseTime is the reference modification time; entry ages are relative to it
+// so fixtures never depend on the wall clock.
+var BaseTime = time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC)
+
+type Entry struct {
+	Path    string // slash-separated, relative to the fixture root
+	Dir     bool
+	Link    string // symlink target, relative to the entry's directory
+	Data    []byte
+	ModTime time.Time
+}
+
+func file(path string, size int, age time.Duration) Entry {
+	return Entry{Path: path, Data: content(path, size), ModTime: BaseTime.Add(-age)}
+}
+
+func text(path, body string, age time.Duration) Entry {
+	return Entry{Path: path, Data: []byte(body), ModTime: BaseTime.Add(-age)}
+}
+
+func dir(path string, age time.Duration) Entry {
+	return Entry{Path: path, Dir: true, ModTime: BaseTime.Add(-age)}
+}
+
+func link(path, target string) Entry {
+	return Entry{Path: path, Link: target, ModTime: BaseTime}
+}
+
+// content returns size bytes that differ per path, so files are only
+// duplicates of each other when a fixture says so.
+func content(seed string, size int) []byte {
+	pattern := []byte(seed + "\x00")
+	return bytes.Repeat(pattern, size/len(pattern)+1)[:size]
+}
+
+const (
+	day  = 24 * time.Hour
+	year = 365 * day
+)
+
+// Entries returns the default fixture tree: a mix of code, text, media and
+// archive files of varying sizes and ages, hidden files, nested and empty
+// directories, duplicates and symlinks (including one that loops).
+func Entries() []Entry {
+	return []Entry{
+		text("README.md", "# Fixture\n\nA small project.\n", 2*day),
+		text("main.go", "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n", 3*day),
+		text("notes.txt", "first\n\nsecond\nthird\nfourth\n", 40*day),
+		file("video.mp4", 3<<20, 2*year),
+		file("photo.jpg", 200<<10, 400*day),
+		file("backup.zip", 1<<20, 3*year),
+		file("debug.log", 64<<10, 10*day),
+		file("scratch.tmp", 512, time.Hour),
+		file("empty.txt", 0, 5*day),
+		text("copy-a.txt", "same content\n", 20*day),
+		text("copy-b.txt", "same content\n", 10*day),
+		text(".env", "SECRET=1\n", day),
+		dir(".cache", 30*day),
+		file(".cache/blob.bin", 8<<10, 30*day),
+		dir("src", 7*day),
+		text("src/app.py", "def main():\n    return 0\n", 7*day),
+		text("src/style.css", "body { margin: 0; }\n", 8*day),
+		dir("src/lib", 9*day),
+		text("src/lib/util.js", "export const id = x => x\n", 9*day),
+		dir("src/lib/deep", 100*day),
+		dir("src/lib/deep/er", 100*day),
+		file("src/lib/deep/er/data.json", 2<<10, 100*day),
+		dir("node_modules", 60*day),
+		dir("node_modules/pkg", 60*day),
+		text("node_modules/pkg/index.js", "module.exports = {}\n", 60*day),
+		dir("empty", 15*day),
+		link("latest.mp4", "video.mp4"),
+		link("src-link", "src"),
+		link("dangling", "missing.txt"),
+		link("src/lib/loop", ".."),
+	}
+}
+
+// Build writes the default tree below root, which must already exist.
+func Build(root string) error {
+	entries := Entries()
+	for _, e := range entries {
+		path := filepath.Join(root, filepath.FromSlash(e.Path))
+
+		switch {
+		case e.Dir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+		case e.Link != "":
+			if err := os.Symlink(filepath.FromSlash(e.Link), path); err != nil {
+				return err
+			}
+		default:
+			if err := os.WriteFile(path, e.Data, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Set times deepest first so creating children doesn't bump them again
+	sort.Slice(entries, func(i, j int) bool {
+		return len(entries[i].Path) > len(entries[j].Path)
+	})
+	for _, e := range entries {
+		if e.Link != "" {
+			continue
+		}
+		path := filepath.Join(root, filepath.FromSlash(e.Path))
+		if err := os.Chtimes(path, e.ModTime, e.ModTime); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TempDir builds the default tree in a fresh temporary directory and returns
+// its path along with a function that removes it.
+func TempDir() (string, func(), error) {
+	root, err := os.MkdirTemp("", "dinder-fixture-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(root) }
+
+	if err := Build(root); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return root, cleanup, nil
+}
+
+// FS returns the default tree as an in-memory filesystem. Symlinks are
+// stored with fs.ModeSymlink and their target as data.
+func FS() fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for _, e := range Entries() {
+		f := &fstest.MapFile{ModTime: e.ModTime, Mode: 0o644}
+		switch {
+		case e.Dir:
+			f.Mode = fs.ModeDir | 0o755
+		case e.Link != "":
+			f.Mode = fs.ModeSymlink | 0o777
+			f.Data = []byte(e.Link)
+		default:
+			f.Data = e.Data
+		}
+		fsys[e.Path] = f
+	}
+	return fsys
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// keepList is the permanent allowlist of paths the user never wants to be
+// asked about again. Entries are absolute paths or globs; an entry without a
+// path separator matches by file name anywhere.
+type keepList []string
+
+// keepListPath is where protected paths are stored, usually
+// ~/.config/dinder/keep.txt.
+func keepListPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "dinder", "keep.txt")
+}
+
+// loadKeepList reads the keep file, one entry per line. Blank lines and
+// lines starting with # are ignored, and a missing file is an empty list.
+func loadKeepList(path string) (keepList, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var list keepList
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				line = filepath.Join(home, rest)
+			}
+		}
+		list = append(list, filepath.Clean(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return list, nil
+}
+
+// Match reports whether path is protected. Protecting a directory protects
+// everything in it, since the scanner never walks into a skipped directory.
+func (k keepList) Match(path string) bool {
+	if len(k) == 0 {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, entry := range k {
+		if entry == abs {
+			return true
+		}
+		target := abs
+		if !strings.ContainsRune(entry, filepath.Separator) {
+			target = filepath.Base(abs)
+		}
+		if matched, _ := filepath.Match(entry, target); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// protectPath appends the absolute form of path to the keep file.
+func protectPath(keepFile, path string) error {
+	if keepFile == "" {
+		return fmt.Errorf("no config directory to store the keep list in")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(keepFile), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(keepFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, abs); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
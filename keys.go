@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Review actions that can be rebound in the config file.
+const (
+	actionKeep   = "keep"
+	actionDelete = "delete"
+	actionSkip   = "skip"
+	actionUndo   = "undo"
+	actionQuit   = "quit"
+)
+
+// keyActions lists the bindable actions in the order conflicts are
+// resolved: an earlier action keeps a key two actions claim.
+var keyActions = []string{actionKeep, actionDelete, actionSkip, actionUndo, actionQuit}
+
+var defaultKeyBindings = map[string][]string{
+	actionKeep:   {"right", "l", "y"},
+	actionDelete: {"left", "h", "n"},
+	actionSkip:   {"s"},
+	actionUndo:   {"u"},
+	actionQuit:   {"q"},
+}
+
+// fixedKeys are the keys review handles itself. Binding an action to one
+// would silently take it away, so such bindings are dropped with a warning.
+var fixedKeys = []string{
+	"m", "M", "z", "e", "p", "v", "/", " ", "D", "K", "P", "o", "+", "=", "-",
+	"esc", "ctrl+r", "ctrl+c", "?",
+}
+
+// keyMap holds the keys bound to each review action.
+type keyMap map[string][]string
+
+// action translates a pressed key into the action bound to it. Keys that
+// aren't bound to an action are returned unchanged.
+func (k keyMap) action(key string) string {
+	for _, action := range keyActions {
+		for _, bound := range k[action] {
+			if bound == key {
+				return action
+			}
+		}
+	}
+	return key
+}
+
+// label renders the keys of action for button and help text, e.g. "→/l/y".
+func (k keyMap) label(action string) string {
+	if len(k[action]) == 0 {
+		return "unbound"
+	}
+	keys := make([]string, len(k[action]))
+	for i, key := range k[action] {
+		switch key {
+		case "right":
+			key = "→"
+		case "left":
+			key = "←"
+		case "up":
+			key = "↑"
+		case "down":
+			key = "↓"
+		}
+		keys[i] = key
+	}
+	return strings.Join(keys, "/")
+}
+
+// keyConfigPath is where the optional config file lives, usually
+// ~/.config/dinder/config.toml.
+func keyConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "dinder", "config.toml")
+}
+
+// loadKeyMap reads the [keys] table of the config file at path:
+//
+//	[keys]
+//	keep = ["right", "k"]
+//	delete = "d"
+//
+// Actions the file doesn't mention keep their default keys, and a missing
+// file means all defaults. Keys claimed by more than one action are reported
+// as warnings; the first action in keyActions keeps them. Keys in fixedKeys
+// are never bound.
+func loadKeyMap(path string) (keyMap, []string, error) {
+	keys := keyMap{}
+	for action, defaults := range defaultKeyBindings {
+		keys[action] = defaults
+	}
+	var warnings []string
+	err := readConfigSection(path, "keys", func(n int, name, value string) error {
+		if _, known := defaultKeyBindings[name]; !known {
+			warnings = append(warnings, fmt.Sprintf("%s:%d: unknown action %q", path, n, name))
+			return nil
+		}
+		bound, err := parseKeyList(value)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %v", path, n, err)
+		}
+		keys[name] = bound
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Resolve keys bound to several actions in favour of the first one
+	owner := map[string]string{}
+	for _, action := range keyActions {
+		var kept []string
+		for _, key := range keys[action] {
+			if slices.Contains(fixedKeys, key) {
+				warnings = append(warnings, fmt.Sprintf("key %q is used by review itself and can't be bound to %s", key, action))
+				continue
+			}
+			if other, taken := owner[key]; taken {
+				warnings = append(warnings, fmt.Sprintf("key %q is bound to both %s and %s; using it for %s", key, other, action, other))
+				continue
+			}
+			owner[key] = action
+			kept = append(kept, key)
+		}
+		keys[action] = kept
+	}
+
+	return keys, warnings, nil
+}
+
+// readConfigSection calls fn with the line number, name and value of every
+// name = value line in the [section] table of the config file at path.
+// Lines elsewhere are ignored, and a missing file has no lines at all.
+func readConfigSection(path, section string, fn func(n int, name, value string) error) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	current := ""
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if current != section {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("%s:%d: expected name = value", path, n)
+		}
+		if err := fn(n, strings.TrimSpace(name), strings.TrimSpace(value)); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// parseKeyList accepts a quoted string or an array of quoted strings.
+func parseKeyList(value string) ([]string, error) {
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	items := []string{value}
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		items = strings.Split(value[1:len(value)-1], ",")
+	}
+
+	var keys []string
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, err := strconv.Unquote(item)
+		if err != nil || key == "" {
+			return nil, fmt.Errorf("invalid key %s", item)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys given")
+	}
+	return keys, nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadKeyMapFixedKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	config := "[keys]\nkeep = [\"k\", \"z\"]\ndelete = \"D\"\n"
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, warnings, err := loadKeyMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(keys[actionKeep], []string{"k"}) {
+		t.Errorf("keep = %v, want [k]", keys[actionKeep])
+	}
+	if len(keys[actionDelete]) != 0 {
+		t.Errorf("delete = %v, want nothing", keys[actionDelete])
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], `"z"`) || !strings.Contains(warnings[1], `"D"`) {
+		t.Errorf("warnings = %q, want one for z and one for D", warnings)
+	}
+	if got := keys.action("z"); got != "z" {
+		t.Errorf(`action("z") = %q, want the fixed key back`, got)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// runListOnly scans like a review would, with every filter applied, and
+// prints the files instead of starting the TUI: one path per line, or the
+// full FileItems as JSON with --json. Nothing is reviewed or deleted.
+func runListOnly(cfg Config, w io.Writer) error {
+	// Previews are never printed, so don't spend time reading them
+	cfg.NoPreview = true
+
+	warn := func(e scanError) {
+		fmt.Fprintf(os.Stderr, "Warning: skipped %s: %v\n", e.Path, e.Err)
+	}
+
+	var files []FileItem
+	collect := func(item FileItem) {
+		files = append(files, item)
+	}
+	switch {
+	case cfg.Archive != "":
+		var err error
+		if files, err = scanArchive(cfg.Archive, cfg); err != nil {
+			return err
+		}
+	case cfg.Stdin:
+		if err := scanPaths(cfg.Paths, cfg, collect, warn); err != nil {
+			return err
+		}
+	default:
+		for _, root := range cfg.Dirs {
+			err := walkDirectory(root, cfg, func(item FileItem) {
+				if len(cfg.Dirs) > 1 {
+					item.Root = root
+				}
+				collect(item)
+			}, warn)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if cfg.FindDupes {
+		files = findDuplicates(files)
+	}
+	if cfg.Sort != "" {
+		sortFiles(files, cfg.Sort, cfg.JunkWeights)
+	}
+	if cfg.Suggest {
+		now := time.Now()
+		for i := range files {
+			files[i].Suggested, _ = suggestDeletion(files[i], cfg.JunkWeights, now)
+		}
+	}
+
+	if cfg.JSON {
+		if files == nil {
+			files = []FileItem{}
+		}
+		data, err := json.MarshalIndent(files, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+	for _, file := range files {
+		if _, err := fmt.Fprintln(w, file.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
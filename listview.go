@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// --list shows every file in one scrollable table instead of card by card.
+// Files are selected with space and decided in bulk; enter goes on to the
+// usual confirmation screen.
+
+var listCursorStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#FAFAFA")).
+	Background(lipgloss.Color("#7D56F4"))
+
+// listHeight is how many table rows fit below the header and above the key
+// hints.
+func (m model) listHeight() int {
+	if m.height == 0 {
+		return len(m.files)
+	}
+	return max(m.height-9, 3)
+}
+
+// moveListCursor moves the cursor by delta rows, scrolling the table so the
+// cursor stays visible.
+func (m model) moveListCursor(delta int) model {
+	m.listCursor = min(max(m.listCursor+delta, 0), len(m.files)-1)
+	height := m.listHeight()
+	if m.listCursor < m.listOffset {
+		m.listOffset = m.listCursor
+	} else if m.listCursor >= m.listOffset+height {
+		m.listOffset = m.listCursor - height + 1
+	}
+	return m
+}
+
+// listTargets are the rows a decision applies to: the selection, or the
+// row under the cursor when nothing is selected.
+func (m model) listTargets() []int {
+	var targets []int
+	for i := range m.files {
+		if m.listSelected[i] {
+			targets = append(targets, i)
+		}
+	}
+	if len(targets) == 0 {
+		targets = []int{m.listCursor}
+	}
+	return targets
+}
+
+func (m model) handleListInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Bound keys come first, so e.g. keep = "k" works here as in review
+	switch action := m.cfg.Keys.action(msg.String()); action {
+	case actionKeep, actionDelete:
+		for _, i := range m.listTargets() {
+			if action == actionKeep || m.canDelete(m.files[i]) {
+				m.files[i].Skipped = false
+				m.decide(i, action == actionKeep)
+			}
+		}
+		m.listSelected = make(map[int]bool)
+		return m, nil
+	case actionQuit:
+		return m, tea.Quit
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		return m.moveListCursor(-1), nil
+	case "down", "j":
+		return m.moveListCursor(1), nil
+	case "pgup":
+		return m.moveListCursor(-m.listHeight()), nil
+	case "pgdown":
+		return m.moveListCursor(m.listHeight()), nil
+	case "home", "g":
+		return m.moveListCursor(-len(m.files)), nil
+	case "end", "G":
+		return m.moveListCursor(len(m.files)), nil
+	case " ":
+		if m.listSelected[m.listCursor] {
+			delete(m.listSelected, m.listCursor)
+		} else {
+			m.listSelected[m.listCursor] = true
+		}
+		return m.moveListCursor(1), nil
+	case "a":
+		// Select everything, or clear the selection if that's already done
+		all := len(m.listSelected) < len(m.files)
+		m.listSelected = make(map[int]bool)
+		if all {
+			for i := range m.files {
+				m.listSelected[i] = true
+			}
+		}
+		return m, nil
+	case "x":
+		for _, i := range m.listTargets() {
+			m.undecide(i)
+		}
+		m.listSelected = make(map[int]bool)
+		return m, nil
+	case "enter":
+		m.currentFile = len(m.files)
+		m.prepareConfirmation()
+		m.screen = ScreenConfirm
+		return m, m.summarizeDirs()
+	}
+	return m, nil
+}
+
+func (m model) listView() string {
+	height := m.listHeight()
+	end := min(m.listOffset+height, len(m.files))
+
+	nameWidth := 40
+	if m.width > 0 {
+		nameWidth = max(m.width-44, 20)
+	}
+
+	var rows []string
+	for i := m.listOffset; i < end; i++ {
+		file := m.files[i]
+		check := "[ ]"
+		if m.listSelected[i] {
+			check = "[x]"
+		}
+		mark := " "
+		switch decisionOf(file) {
+		case "keep":
+			mark = "✓"
+		case "delete":
+			mark = "✗"
+		}
+		// Padded by display width; %-*s would count wide runes as one cell
+		name := runewidth.FillRight(fitPath(m.displayPath(file.Path), nameWidth), nameWidth)
+		row := fmt.Sprintf("%s %s %s %s %10s  %s", check, mark, itemIcon(file), name,
+			formatSize(file.Size), file.ModTime.Format("2006-01-02"))
+		if i == m.listCursor {
+			row = listCursorStyle.Render(row)
+		}
+		rows = append(rows, row)
+	}
+	if end < len(m.files) || m.listOffset > 0 {
+		rows = append(rows, disabledHintStyle.Render(fmt.Sprintf("  %d-%d of %d", m.listOffset+1, end, len(m.files))))
+	}
+
+	selected := ""
+	if n := len(m.listSelected); n > 0 {
+		selected = fmt.Sprintf(" | %d selected", n)
+	}
+	return fmt.Sprintf("\n%s\n\n%s%s\n\n%s\n\nspace=select | a=select all | %s=delete | %s=keep | x=clear decision | enter=confirm | %s=quit",
+		titleStyle.Render("File List"), m.statusBar(), selected, strings.Join(rows, "\n"),
+		m.cfg.Keys.label(actionDelete), m.cfg.Keys.label(actionKeep), m.cfg.Keys.label(actionQuit))
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockMaxAge is how long a lock is honored even if its process still seems
+// to exist, in case the PID was reused.
+const lockMaxAge = 24 * time.Hour
+
+type lockInfo struct {
+	PID     int       `json:"pid"`
+	Dir     string    `json:"dir"`
+	Started time.Time `json:"started"`
+}
+
+// dirLock marks a directory as being reviewed by this process. Lock files
+// live in the user cache directory rather than in the reviewed tree.
+type dirLock struct {
+	path string
+}
+
+func lockPath(dir string) (string, string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	state, err := stateDir()
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	name := hex.EncodeToString(sum[:8]) + ".lock"
+	return filepath.Join(state, "locks", name), abs, nil
+}
+
+// acquireLock takes the lock for dir. If another live instance holds it, the
+// returned lock is nil and holder describes that instance. Stale locks left
+// by crashed runs are replaced silently.
+func acquireLock(dir string) (*dirLock, *lockInfo, error) {
+	path, abs, err := lockPath(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, nil, err
+	}
+
+	info := lockInfo{PID: os.Getpid(), Dir: abs, Started: time.Now()}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			_, werr := file.Write(data)
+			cerr := file.Close()
+			if werr != nil || cerr != nil {
+				os.Remove(path)
+				return nil, nil, errors.Join(werr, cerr)
+			}
+			return &dirLock{path: path}, nil, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, nil, err
+		}
+
+		if holder := liveLock(path); holder != nil {
+			return nil, holder, nil
+		}
+		// Stale or unreadable lock: clear it and try again
+		os.Remove(path)
+	}
+
+	return nil, nil, errors.New("could not acquire directory lock")
+}
+
+// liveLock returns the holder of the lock file at path while that instance
+// still counts as running, or nil for stale and unreadable locks.
+func liveLock(path string) *lockInfo {
+	var holder lockInfo
+	raw, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(raw, &holder) != nil {
+		return nil
+	}
+	if time.Since(holder.Started) >= lockMaxAge || !processAlive(holder.PID) {
+		return nil
+	}
+	return &holder
+}
+
+// Release removes the lock file. It is safe to call on a nil lock.
+func (l *dirLock) Release() {
+	if l != nil {
+		os.Remove(l.path)
+	}
+}
@@ -1,16 +1,158 @@
 package main
 
 import (
+	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	if len(os.Args) > 1 && os.Args[1] == "clean" {
+		if err := runClean(os.Args[2:]); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				os.Exit(0)
+			}
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg, err := parseConfig(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	if cfg.Stdin {
+		cfg.Paths, err = readPathList(os.Stdin, func(warning string) {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := checkTargets(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	if cfg.ListOnly {
+		if err := runListOnly(cfg, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var locks []*dirLock
+	releaseLocks := func() {
+		for _, lock := range locks {
+			lock.Release()
+		}
+	}
+	lockDirs := cfg.Dirs
+	if cfg.Archive != "" || cfg.Stdin {
+		// Dirs is only the "." default then; nothing in it is reviewed
+		lockDirs = nil
+	}
+	for _, dir := range lockDirs {
+		lock, holder, err := acquireLock(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not lock directory: %v\n", err)
+		}
+		locks = append(locks, lock)
+		if holder != nil && !cfg.Audit {
+			if !askReadOnly(holder) {
+				releaseLocks()
+				os.Exit(1)
+			}
+			cfg.Audit = true
+		}
+	}
+
+	m := initialModel(cfg)
+	if cfg.Resume != "" {
+		m.session, err = loadSession(cfg.Resume, cfg.Dir)
+		if err != nil {
+			releaseLocks()
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	setupColors()
+	setupGraphics()
+	setupIcons(cfg.ASCII, cfg.Icons)
+
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if cfg.Mouse {
+		// Off by default: it takes over the terminal's own text selection
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	if cfg.Stdin {
+		// Standard input held the path list; keys come from the terminal
+		opts = append(opts, tea.WithInputTTY())
+	}
+	p := tea.NewProgram(m, opts...)
+	final, err := p.Run()
+	releaseLocks()
+	if err != nil {
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
 	}
+	if m, ok := final.(model); ok {
+		if m.err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", m.err)
+			os.Exit(1)
+		}
+		if cfg.DryRun {
+			m.writeDryRunLog(os.Stdout)
+		}
+		if cfg.Report != "" {
+			if err := m.writeDeletionReport(cfg.Report); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: writing report: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if m.cfg.SaveSession != "" {
+			if err := m.saveSession(m.cfg.SaveSession); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: saving session: %v\n", err)
+				os.Exit(1)
+			}
+			if cfg.SaveSession == "" {
+				// Chosen at the quit prompt rather than on the command line
+				fmt.Printf("Decisions saved to %s; continue with: dinder --resume %s\n", m.cfg.SaveSession, m.cfg.SaveSession)
+			}
+		}
+	}
+}
+
+// askReadOnly tells the user another instance is working on the directory
+// and asks whether to continue in read-only audit mode instead. The answer
+// is read from the terminal, which standard input need not be.
+func askReadOnly(holder *lockInfo) bool {
+	fmt.Fprintf(os.Stderr, "Another dinder (pid %d, started %s) is reviewing this directory.\n",
+		holder.PID, holder.Started.Format("2006-01-02 15:04"))
+	fmt.Fprint(os.Stderr, "Continue read-only (audit mode, nothing is deleted)? [y/N] ")
+
+	var in io.Reader = os.Stdin
+	if tty, err := os.Open("/dev/tty"); err == nil {
+		defer tty.Close()
+		in = tty
+	}
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
 }
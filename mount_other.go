@@ -0,0 +1,30 @@
+//go:build !unix
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// sameFilesystem compares volume names, the closest cheap approximation of
+// a device check on this platform.
+func sameFilesystem(path, dir string) bool {
+	a, errA := filepath.Abs(path)
+	b, errB := filepath.Abs(dir)
+	if errA != nil || errB != nil {
+		return true
+	}
+	return strings.EqualFold(filepath.VolumeName(a), filepath.VolumeName(b))
+}
+
+// dirIdentity falls back to the fully resolved path where there are no
+// inode numbers to compare.
+func dirIdentity(path string) (string, bool) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", false
+	}
+	abs, err := filepath.Abs(resolved)
+	return strings.ToLower(abs), err == nil
+}
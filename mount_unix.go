@@ -0,0 +1,46 @@
+//go:build unix
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// sameFilesystem reports whether path and the directory dir (or its nearest
+// existing parent) live on the same device.
+func sameFilesystem(path, dir string) bool {
+	src, err := os.Lstat(path)
+	if err != nil {
+		return true
+	}
+	for {
+		dst, err := os.Stat(dir)
+		if err == nil {
+			a, okA := src.Sys().(*syscall.Stat_t)
+			b, okB := dst.Sys().(*syscall.Stat_t)
+			return !okA || !okB || a.Dev == b.Dev
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return true
+		}
+		dir = parent
+	}
+}
+
+// dirIdentity names the directory path resolves to by device and inode, so
+// the same directory reached through different symlinks compares equal.
+func dirIdentity(path string) (string, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%d:%d", st.Dev, st.Ino), true
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// reviewButton is one of the buttons under the review card, along with the
+// review action a click on it performs.
+type reviewButton struct {
+	view   string
+	action string
+}
+
+const buttonGap = "  "
+
+func (m model) reviewButtons(file FileItem) []reviewButton {
+	keep := reviewButton{keepButtonStyle.Render(fmt.Sprintf("✓ Keep (%s)", m.cfg.Keys.label(actionKeep))), actionKeep}
+	del := reviewButton{deleteButtonStyle.Render(fmt.Sprintf("✗ Delete (%s)", m.cfg.Keys.label(actionDelete))), actionDelete}
+	if !m.canDelete(file) {
+		del = reviewButton{buttonStyle.Render("✗ Delete (disabled)"), ""}
+	}
+	skip := reviewButton{buttonStyle.Render(fmt.Sprintf("↷ Skip (%s)", m.cfg.Keys.label(actionSkip))), actionSkip}
+
+	buttons := []reviewButton{keep, del, skip}
+	if m.cfg.MoveTo != "" && m.cfg.Archive == "" {
+		buttons = append(buttons, reviewButton{buttonStyle.Render("⇢ Move (m) / + symlink (M)"), "m"})
+	}
+	return buttons
+}
+
+func joinButtons(buttons []reviewButton) string {
+	views := make([]string, 0, 2*len(buttons))
+	for i, b := range buttons {
+		if i > 0 {
+			views = append(views, buttonGap)
+		}
+		views = append(views, b.view)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, views...)
+}
+
+// buttonAt finds the review button drawn at screen cell x, y. The buttons
+// are located in the rendered view, which is cheaper to get right than
+// re-deriving the card layout above them.
+func (m model) buttonAt(x, y int) string {
+	if m.currentFile >= len(m.files) {
+		return ""
+	}
+	buttons := m.reviewButtons(m.files[m.currentFile])
+	joined := joinButtons(buttons)
+
+	// Not View, which hands out pending bells
+	view := m.view()
+	at := strings.Index(view, joined)
+	if at < 0 {
+		// A header card is showing instead of a file
+		return ""
+	}
+	top := strings.Count(view[:at], "\n")
+	if lines := strings.Count(view, "\n") + 1; m.height > 0 && lines > m.height {
+		// The renderer cuts overflowing views off at the top
+		top -= lines - m.height
+	}
+	// The buttons' top margin is blank; only their label row is clickable
+	if y != top+lipgloss.Height(joined)-1 {
+		return ""
+	}
+
+	left := 0
+	for _, b := range buttons {
+		right := left + lipgloss.Width(b.view)
+		if x >= left && x < right {
+			return b.action
+		}
+		left = right + lipgloss.Width(buttonGap)
+	}
+	return ""
+}
+
+// handleMouse turns a click on a review button into the same action its
+// key performs.
+func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.screen != ScreenReview || msg.Type != tea.MouseLeft || m.showHelp || m.searching {
+		return m, nil
+	}
+	action := m.buttonAt(msg.X, msg.Y)
+	if action == "" {
+		return m, nil
+	}
+	m.notice = ""
+	return m.reviewAction(action)
+}
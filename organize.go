@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// organizeRule moves kept files of a category (or a list of extensions) into
+// a destination directory. The destination may start with ~ and may contain
+// {year} and {month}, which are filled in from the file's modification time.
+type organizeRule struct {
+	Match []string
+	Dest  string
+}
+
+// organizeMove is a kept file that will be moved into Dest, either by an
+// --organize-kept rule or by the review's move action. With Link set, a
+// symlink to the new location is left at the original path.
+type organizeMove struct {
+	File         FileItem
+	Dest         string
+	Link         bool
+	CrossesMount bool
+}
+
+// organizeRules is the repeatable --organize-kept flag, e.g.
+// --organize-kept "images=~/Pictures/{year}" --organize-kept ".iso,.dmg=~/Installers".
+type organizeRules []organizeRule
+
+func (r *organizeRules) String() string {
+	var parts []string
+	for _, rule := range *r {
+		parts = append(parts, strings.Join(rule.Match, ",")+"="+rule.Dest)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (r *organizeRules) Set(value string) error {
+	match, dest, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(match) == "" || strings.TrimSpace(dest) == "" {
+		return fmt.Errorf("organize rule %q must look like TYPE=DIR", value)
+	}
+
+	rule := organizeRule{Dest: strings.TrimSpace(dest)}
+	for _, m := range strings.Split(match, ",") {
+		if m = strings.ToLower(strings.TrimSpace(m)); m != "" {
+			rule.Match = append(rule.Match, m)
+		}
+	}
+	*r = append(*r, rule)
+	return nil
+}
+
+// destination returns the directory a kept file should be moved to, or ""
+// if no rule applies.
+func (r organizeRules) destination(file FileItem) string {
+	if file.IsDir {
+		return ""
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Path))
+	category := fileCategory(file.Path)
+
+	for _, rule := range r {
+		for _, m := range rule.Match {
+			if m == category || m == ext || "."+m == ext {
+				return expandDest(rule.Dest, file)
+			}
+		}
+	}
+	return ""
+}
+
+func expandDest(dest string, file FileItem) string {
+	if dest == "~" || strings.HasPrefix(dest, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dest = filepath.Join(home, dest[1:])
+		}
+	}
+	dest = strings.ReplaceAll(dest, "{year}", file.ModTime.Format("2006"))
+	dest = strings.ReplaceAll(dest, "{month}", file.ModTime.Format("01"))
+	return filepath.Clean(dest)
+}
+
+// moveFile moves src into the directory dir, picking a free name if the
+// target already exists and copying when dir is on another filesystem.
+func moveFile(src, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	dst := freePath(filepath.Join(dir, filepath.Base(src)))
+	err := os.Rename(src, dst)
+	if errors.Is(err, syscall.EXDEV) {
+		err = copyAndRemove(src, dst)
+	}
+	if err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+func freePath(path string) string {
+	if _, err := os.Lstat(path); errors.Is(err, os.ErrNotExist) {
+		return path
+	}
+
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if _, err := os.Lstat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate
+		}
+	}
+}
+
+// copyAndRemove moves src to dst where a rename can't, across
+// filesystems. Directories are copied with everything in them and symlinks
+// are recreated rather than followed; src is only removed once the whole
+// copy has succeeded, and a partial copy is cleaned up again.
+func copyAndRemove(src, dst string) error {
+	if err := copyPath(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+func copyPath(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+
+	switch mode := info.Mode(); {
+	case mode&fs.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+	case mode.IsDir():
+		// Writable until everything inside is copied, even for read-only
+		// directories
+		if err := os.Mkdir(dst, mode.Perm()|0o700); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := copyPath(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+				return err
+			}
+		}
+		os.Chmod(dst, mode.Perm())
+		os.Chtimes(dst, info.ModTime(), info.ModTime())
+		return nil
+	case !mode.IsRegular():
+		return fmt.Errorf("%s: only files, directories and symlinks can be moved to another filesystem", src)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	os.Chtimes(dst, info.ModTime(), info.ModTime())
+	return nil
+}
+
+// linkBack replaces the original path with a symlink to where the file now
+// lives, so existing references keep working.
+func linkBack(dst, original string) error {
+	target, err := filepath.Abs(dst)
+	if err != nil {
+		return err
+	}
+	return os.Symlink(target, original)
+}
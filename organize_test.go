@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyAndRemoveDirectory(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "project")
+	for _, dir := range []string{"src", "docs"} {
+		if err := os.MkdirAll(filepath.Join(src, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(src, "src", "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("src/main.go", filepath.Join(src, "entry")); err != nil {
+		t.Fatal(err)
+	}
+	// Read-only directories still get their contents copied
+	if err := os.Chmod(filepath.Join(src, "docs"), 0o555); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "project")
+	if err := copyAndRemove(src, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Lstat(src); !os.IsNotExist(err) {
+		t.Errorf("source still exists after the move: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dst, "src", "main.go")); err != nil || string(data) != "package main\n" {
+		t.Errorf("nested file = %q, %v", data, err)
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "entry")); err != nil || target != "src/main.go" {
+		t.Errorf("symlink points to %q, %v; want it recreated as src/main.go", target, err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "docs")); err != nil || info.Mode().Perm() != 0o555 {
+		t.Errorf("read-only directory copied as %v, %v", info.Mode().Perm(), err)
+	}
+	os.Chmod(filepath.Join(dst, "docs"), 0o755)
+}
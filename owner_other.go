@@ -0,0 +1,19 @@
+//go:build !unix
+
+package main
+
+import (
+	"io/fs"
+	"os"
+)
+
+// fileOwner is not available on this platform; the card just omits it.
+func fileOwner(info fs.FileInfo) (string, string) {
+	return "", ""
+}
+
+// canWrite falls back to the owner write bit where there is no access(2).
+func canWrite(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode().Perm()&0o200 != 0
+}
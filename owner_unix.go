@@ -0,0 +1,58 @@
+//go:build unix
+
+package main
+
+import (
+	"io/fs"
+	"os/user"
+	"strconv"
+	"sync"
+	"syscall"
+)
+
+var (
+	ownerNames sync.Map
+	groupNames sync.Map
+)
+
+// fileOwner resolves the owning user and group of a file to names, falling
+// back to the numeric ids when they are not in the user database.
+func fileOwner(info fs.FileInfo) (string, string) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", ""
+	}
+	return lookupName(&ownerNames, stat.Uid, func(id string) (string, error) {
+			u, err := user.LookupId(id)
+			if err != nil {
+				return "", err
+			}
+			return u.Username, nil
+		}), lookupName(&groupNames, stat.Gid, func(id string) (string, error) {
+			g, err := user.LookupGroupId(id)
+			if err != nil {
+				return "", err
+			}
+			return g.Name, nil
+		})
+}
+
+func lookupName(cache *sync.Map, id uint32, lookup func(string) (string, error)) string {
+	if name, ok := cache.Load(id); ok {
+		return name.(string)
+	}
+	key := strconv.FormatUint(uint64(id), 10)
+	name, err := lookup(key)
+	if err != nil {
+		name = key
+	}
+	cache.Store(id, name)
+	return name
+}
+
+// canWrite reports whether the current user may write to path, asking the
+// kernel so group membership and root are accounted for.
+func canWrite(path string) bool {
+	const wOK = 2
+	return syscall.Access(path, wOK) == nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+// renderPreview is the single place preview text of file is turned into
+// what the card shows. In raw mode the text is shown exactly as read from
+// disk, and images are described instead of drawn; otherwise each type gets
+// its rich rendering (pretty JSON, highlighted code and markdown) using the
+// chroma style named by theme.
+func renderPreview(text string, file FileItem, theme string, raw bool) string {
+	if file.IsDir {
+		// A listing of names, whatever the directory happens to be called
+		return text
+	}
+	if raw && isImageFile(file.Path) && file.ImageInfo != "" {
+		return file.ImageInfo
+	}
+	if !raw && file.Graphic != "" {
+		// The real image is drawn over these cells afterwards
+		return graphicPlaceholder(text)
+	}
+	if raw {
+		return text
+	}
+
+	path := file.Path
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var pretty bytes.Buffer
+		// Previews are often cut off mid-document; keep the text then
+		if json.Indent(&pretty, []byte(text), "", "  ") == nil {
+			text = pretty.String()
+		}
+		return applySyntaxHighlighting(text, path, theme)
+	case ".md", ".markdown":
+		return applySyntaxHighlighting(text, path, theme)
+	}
+
+	if isCodeFile(path) {
+		return applySyntaxHighlighting(text, path, theme)
+	}
+	return text
+}
+
+// lexerFor picks the chroma lexer for path, by file name first and then by
+// a few common extensions the name patterns miss. It returns nil for plain
+// text.
+func lexerFor(path string) chroma.Lexer {
+	if lexer := lexers.Match(path); lexer != nil {
+		return lexer
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		return lexers.Get("go")
+	case ".js":
+		return lexers.Get("javascript")
+	case ".ts":
+		return lexers.Get("typescript")
+	case ".py":
+		return lexers.Get("python")
+	case ".json":
+		return lexers.Get("json")
+	case ".md":
+		return lexers.Get("markdown")
+	case ".html":
+		return lexers.Get("html")
+	case ".css":
+		return lexers.Get("css")
+	case ".xml":
+		return lexers.Get("xml")
+	case ".yaml", ".yml":
+		return lexers.Get("yaml")
+	case ".sh", ".bash":
+		return lexers.Get("bash")
+	}
+	return nil
+}
+
+// languageName is the display name of the language lexerFor detects, or ""
+// when there is none.
+func languageName(path string) string {
+	if lexer := lexerFor(path); lexer != nil {
+		return lexer.Config().Name
+	}
+	return ""
+}
+
+// codePreviewHeader titles the code preview box with the file's language
+// and length, e.g. "Go · 482 lines".
+func codePreviewHeader(file FileItem, raw bool) string {
+	var parts []string
+	if file.Language != "" {
+		parts = append(parts, file.Language)
+	}
+	if file.TotalLines > 0 {
+		unit := "lines"
+		if file.TotalLines == 1 && !file.MoreLines {
+			unit = "line"
+		}
+		count := formatCount(file.TotalLines)
+		if file.MoreLines {
+			// Counting stopped partway through a huge file
+			count += "+"
+		}
+		parts = append(parts, count+" "+unit)
+	}
+	header := "Code Preview"
+	if len(parts) > 0 {
+		header = strings.Join(parts, " · ")
+	}
+	if raw {
+		header += " (raw)"
+	}
+	return header
+}
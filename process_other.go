@@ -0,0 +1,17 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	proc.Release()
+	return true
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type reportEntry struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"`
+	IsDir    bool      `json:"is_dir"`
+	Decision string    `json:"decision"`
+}
+
+type auditReport struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Root        string         `json:"root"`
+	Summary     map[string]int `json:"summary"`
+	DeleteBytes int64          `json:"delete_bytes"`
+	Entries     []reportEntry  `json:"entries"`
+}
+
+type auditWrittenMsg struct {
+	path string
+	err  error
+}
+
+func decisionOf(file FileItem) string {
+	switch {
+	case file.Decided && file.Keep:
+		return "keep"
+	case file.Decided:
+		return "delete"
+	case file.Skipped:
+		return "skip"
+	default:
+		return "undecided"
+	}
+}
+
+// writeAuditReport records every review decision without touching any of the
+// files, so someone else can approve them before a real run.
+func writeAuditReport(path, root string, files []FileItem) error {
+	report := auditReport{
+		GeneratedAt: time.Now(),
+		Root:        root,
+		Summary:     map[string]int{},
+		Entries:     []reportEntry{},
+	}
+	if abs, err := filepath.Abs(root); err == nil {
+		report.Root = abs
+	}
+
+	for _, file := range files {
+		decision := decisionOf(file)
+		report.Summary[decision]++
+		if decision == "delete" {
+			report.DeleteBytes += file.Size
+		}
+		report.Entries = append(report.Entries, reportEntry{
+			Path:     file.Path,
+			Size:     file.Size,
+			ModTime:  file.ModTime,
+			IsDir:    file.IsDir,
+			Decision: decision,
+		})
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func (m model) writeAudit() tea.Cmd {
+	path := m.cfg.AuditReport
+	root := m.cfg.Dir
+	files := append([]FileItem(nil), m.files...)
+	return func() tea.Msg {
+		return auditWrittenMsg{path: path, err: writeAuditReport(path, root, files)}
+	}
+}
+
+type deletionEntry struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	IsDir   bool      `json:"is_dir"`
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+}
+
+type deletionSummary struct {
+	Deleted          int   `json:"deleted"`
+	WouldDelete      int   `json:"would_delete,omitempty"`
+	Failed           int   `json:"failed"`
+	AlreadyGone      int   `json:"already_gone"`
+	NotAttempted     int   `json:"not_attempted"`
+	FreedBytes       int64 `json:"freed_bytes"`
+	WouldFreeBytes   int64 `json:"would_free_bytes,omitempty"`
+	EmptyDirsRemoved int   `json:"empty_dirs_removed"`
+}
+
+type deletionReport struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	Root        string          `json:"root"`
+	DryRun      bool            `json:"dry_run"`
+	Permanent   bool            `json:"permanent"`
+	Summary     deletionSummary `json:"summary"`
+	Entries     []deletionEntry `json:"entries"`
+}
+
+// writeDeletionReport records what happened to every file marked for
+// deletion. main calls it once the TUI exits; nothing is written when the
+// run ended before deletion started.
+func (m model) writeDeletionReport(path string) error {
+	if m.cfg.Audit || (m.screen != ScreenProgress && m.screen != ScreenEmptyDirs && m.screen != ScreenComplete) {
+		return nil
+	}
+
+	failed := make(map[string]error, len(m.failures))
+	for _, f := range m.failures {
+		failed[f.File.Path] = f.Err
+	}
+	// An archive is rewritten in one go, so its entries share one outcome
+	archiveDone := m.cfg.Archive != "" && m.deletedCount > 0
+	wouldDelete := func(path string) bool {
+		return m.cfg.DryRun && (archiveDone || slices.Contains(m.wouldDelete, path))
+	}
+
+	report := deletionReport{
+		GeneratedAt: time.Now(),
+		Root:        m.cfg.Dir,
+		DryRun:      m.cfg.DryRun,
+		Permanent:   m.cfg.Permanent,
+		Summary: deletionSummary{
+			EmptyDirsRemoved: m.emptyRemoved,
+		},
+		Entries: []deletionEntry{},
+	}
+	if m.cfg.DryRun {
+		report.Summary.WouldFreeBytes = m.deletedSize
+	} else {
+		report.Summary.FreedBytes = m.deletedSize
+	}
+	if m.cfg.Archive != "" {
+		report.Root = m.cfg.Archive
+	}
+	if abs, err := filepath.Abs(report.Root); err == nil {
+		report.Root = abs
+	}
+
+	for _, file := range m.toDelete {
+		entry := deletionEntry{
+			Path:    file.Path,
+			Size:    m.deleteSize(file),
+			ModTime: file.ModTime,
+			IsDir:   file.IsDir,
+			Status:  "not_attempted",
+		}
+		i := m.indexOfFile(file.Path)
+		err, isFailed := failed[file.Path]
+		if !isFailed && m.cfg.Archive != "" {
+			err, isFailed = failed[m.cfg.Archive]
+		}
+		switch {
+		case wouldDelete(file.Path):
+			entry.Status = "would_delete"
+			report.Summary.WouldDelete++
+		case archiveDone || (i >= 0 && m.files[i].Deleted):
+			entry.Status = "deleted"
+			report.Summary.Deleted++
+		case slices.Contains(m.alreadyGone, file.Path):
+			entry.Status = "already_gone"
+			report.Summary.AlreadyGone++
+		case isFailed:
+			entry.Status = "failed"
+			if err != nil {
+				entry.Error = err.Error()
+			}
+			report.Summary.Failed++
+		default:
+			report.Summary.NotAttempted++
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// writeDryRunLog lists what a --dry-run confirmation would have done. main
+// prints it once the TUI has released the terminal.
+func (m model) writeDryRunLog(w io.Writer) {
+	if m.screen != ScreenComplete {
+		return
+	}
+	fmt.Fprintln(w, "DRY RUN — no files were deleted")
+	for _, file := range m.toDelete {
+		fmt.Fprintf(w, "would delete: %s (%s)\n", file.Path, formatSize(m.deleteSize(file)))
+	}
+	for _, move := range m.toOrganize {
+		fmt.Fprintf(w, "would move:   %s -> %s\n", move.File.Path, move.Dest)
+	}
+	fmt.Fprintf(w, "%d files, %s would be freed\n", len(m.toDelete), formatSize(m.totalSize))
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scanBatchMsg carries files found since the previous batch, and the paths
+// left out because they couldn't be read. The final batch has done set,
+// along with the error if the scan could not run at all. With
+// --find-dupes a hashing batch announces that the walk is over, and the
+// final batch replaces everything streamed with just the duplicates.
+type scanBatchMsg struct {
+	files   []FileItem
+	done    bool
+	err     error
+	hashing bool
+	replace bool
+	skipped []scanError
+}
+
+type scanStartedMsg struct{ batches <-chan scanBatchMsg }
+type scanEstimateMsg int
+
+const (
+	scanBatchSize  = 200
+	scanBatchDelay = 100 * time.Millisecond
+)
+
+// startScan runs the scanner in the background and streams its results in
+// batches, so review can begin before a large tree is fully walked.
+func startScan(dir string, cfg Config) tea.Cmd {
+	return func() tea.Msg {
+		batches := make(chan scanBatchMsg, 4)
+
+		if cfg.Archive != "" {
+			go func() {
+				defer close(batches)
+				files, err := scanArchive(cfg.Archive, cfg)
+				batches <- scanBatchMsg{files: files, done: true, err: err}
+			}()
+			return scanStartedMsg{batches: batches}
+		}
+
+		go func() {
+			defer close(batches)
+
+			var pending []FileItem
+			var pendingErrs []scanError
+			last := time.Now()
+			var all []FileItem
+			walk := walkDirectory
+			if cfg.Stdin {
+				walk = func(_ string, cfg Config, fn func(FileItem), onErr func(scanError)) error {
+					return scanPaths(cfg.Paths, cfg, fn, onErr)
+				}
+			} else if len(cfg.Dirs) > 1 {
+				// Several roots are walked one after another into a single
+				// queue, each file remembering where it came from
+				walk = func(_ string, cfg Config, fn func(FileItem), onErr func(scanError)) error {
+					for _, root := range cfg.Dirs {
+						err := walkDirectory(root, cfg, func(item FileItem) {
+							item.Root = root
+							fn(item)
+						}, onErr)
+						if err != nil {
+							return err
+						}
+					}
+					return nil
+				}
+			}
+			err := walk(dir, cfg, func(item FileItem) {
+				pending = append(pending, item)
+				if cfg.FindDupes {
+					all = append(all, item)
+				}
+				if len(pending) >= scanBatchSize || time.Since(last) >= scanBatchDelay {
+					batches <- scanBatchMsg{files: pending, skipped: pendingErrs}
+					pending, pendingErrs = nil, nil
+					last = time.Now()
+				}
+			}, func(e scanError) {
+				pendingErrs = append(pendingErrs, e)
+			})
+			if cfg.FindDupes && err == nil {
+				batches <- scanBatchMsg{files: pending, skipped: pendingErrs, hashing: true}
+				batches <- scanBatchMsg{files: findDuplicates(all), done: true, replace: true}
+				return
+			}
+			batches <- scanBatchMsg{files: pending, skipped: pendingErrs, done: true, err: err}
+		}()
+
+		return scanStartedMsg{batches: batches}
+	}
+}
+
+func waitForScan(batches <-chan scanBatchMsg) tea.Cmd {
+	return func() tea.Msg {
+		batch, ok := <-batches
+		if !ok {
+			return nil
+		}
+		return batch
+	}
+}
+
+func estimateFiles(dir string, cfg Config) tea.Cmd {
+	return func() tea.Msg {
+		if cfg.Stdin {
+			return scanEstimateMsg(len(cfg.Paths))
+		}
+		count := 0
+		for _, root := range cfg.Dirs {
+			count += estimateCount(root, cfg)
+		}
+		return scanEstimateMsg(count)
+	}
+}
+
+// handleScanBatch appends streamed files to the queue. Review starts with
+// the first batch unless the queue has to be sorted, which needs every file.
+func (m model) handleScanBatch(msg scanBatchMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = msg.err
+		return m, tea.Quit
+	}
+
+	if msg.replace {
+		m.files, m.scannedSize = nil, 0
+	} else {
+		m.scannedCount += len(msg.files)
+	}
+	m.hashing = msg.hashing
+	m.scanErrors = append(m.scanErrors, msg.skipped...)
+	for i := range msg.files {
+		m.markSuggestion(&msg.files[i])
+	}
+	m.files = append(m.files, msg.files...)
+	for _, file := range msg.files {
+		m.scannedSize += file.Size
+	}
+
+	if !msg.done {
+		if m.screen == ScreenLoading && len(m.files) > 0 && !m.needsFullScan() {
+			m.screen = ScreenReview
+			m.refreshPreview()
+		}
+		return m, waitForScan(m.scanBatches)
+	}
+
+	m.scanning = false
+	m.scanTook = time.Since(m.scanStart)
+	m.estimate = len(m.files)
+	if m.needsFullScan() {
+		sortFiles(m.files, m.cfg.Sort, m.cfg.JunkWeights)
+		if m.cfg.ByAge {
+			groupByAge(m.files, time.Now())
+		}
+		if m.cfg.Group {
+			groupByCategory(m.files)
+		}
+	}
+
+	if m.cfg.Summary && len(m.files) > 0 {
+		m.screen = ScreenSummary
+		return m, nil
+	}
+	return m.startReview()
+}
+
+// startReview moves on from the finished scan to the first file to review.
+func (m model) startReview() (tea.Model, tea.Cmd) {
+	if m.session != nil && len(m.files) > 0 {
+		m.applySession(m.session)
+		m.session = nil
+		if m.cfg.List {
+			m.screen = ScreenList
+			return m, nil
+		}
+		m.screen = ScreenReview
+		m.currentFile = -1
+		next, cmd := m.nextFile()
+		if m.cfg.Watch && m.cfg.Archive == "" {
+			cmd = tea.Batch(cmd, startWatching(m.cfg))
+		}
+		return next, cmd
+	}
+
+	if m.cfg.Watch && m.cfg.Archive == "" {
+		m.screen = ScreenReview
+		return m, startWatching(m.cfg)
+	}
+	if len(m.files) == 0 {
+		m.screen = ScreenComplete
+		return m, nil
+	}
+	if m.cfg.List {
+		m.screen = ScreenList
+		return m, nil
+	}
+	if m.screen == ScreenLoading || m.screen == ScreenSummary || m.currentFile >= len(m.files) {
+		// Either nothing was shown yet, or review caught up with the scan
+		m.screen = ScreenReview
+		if m.currentFile >= len(m.files) {
+			m.currentFile--
+			return m.nextFile()
+		}
+		m.refreshPreview()
+	}
+	return m, nil
+}
+
+// markSuggestion flags a file --suggest thinks can go. It only biases the
+// display; nothing is decided for the user.
+func (m model) markSuggestion(file *FileItem) {
+	if m.cfg.Suggest {
+		file.Suggested, _ = suggestDeletion(*file, m.cfg.JunkWeights, time.Now())
+	}
+}
+
+// needsFullScan reports whether review has to wait for the complete file
+// list, because the queue order depends on all of it.
+func (m model) needsFullScan() bool {
+	return m.cfg.Sort != "" || m.cfg.ByAge || m.cfg.Group || m.cfg.FindDupes || m.cfg.Summary || m.cfg.List || m.session != nil
+}
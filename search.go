@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// matchesSearch reports whether file's path contains query, ignoring case.
+func matchesSearch(file FileItem, query string) bool {
+	return strings.Contains(strings.ToLower(file.Path), strings.ToLower(query))
+}
+
+// firstMatch returns the first file in the queue that still needs a
+// decision and matches query, or -1.
+func (m model) firstMatch(query string) int {
+	for i, file := range m.files {
+		if !file.Decided && !file.Skipped && matchesSearch(file, query) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m model) searchMatches() int {
+	count := 0
+	for _, file := range m.files {
+		if !file.Decided && !file.Skipped && matchesSearch(file, m.searchInput) {
+			count++
+		}
+	}
+	return count
+}
+
+// startSearch opens the search prompt. The queue position is remembered so
+// cancelling, or finishing the matches, returns to it.
+func (m model) startSearch() (tea.Model, tea.Cmd) {
+	if m.search == "" {
+		m.searchReturn = m.currentFile
+	}
+	m.searching = true
+	m.searchInput = m.search
+	return m, nil
+}
+
+// handleSearchInput edits the search prompt. The card follows the first
+// match while typing; enter narrows the queue to the matches and esc
+// restores the queue as it was.
+func (m model) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		return m.clearSearch(), nil
+	case tea.KeyEnter:
+		if m.searchInput == "" {
+			return m.clearSearch(), nil
+		}
+		if i := m.firstMatch(m.searchInput); i >= 0 {
+			m.searching = false
+			m.search = m.searchInput
+			m.currentFile = i
+			m.refreshPreview()
+		}
+		return m, nil
+	case tea.KeyBackspace:
+		runes := []rune(m.searchInput)
+		if len(runes) > 0 {
+			m.searchInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.searchInput += string(msg.Runes)
+	default:
+		return m, nil
+	}
+
+	m.currentFile = m.searchReturn
+	if i := m.firstMatch(m.searchInput); i >= 0 && m.searchInput != "" {
+		m.currentFile = i
+	}
+	m.refreshPreview()
+	return m, nil
+}
+
+// clearSearch leaves search mode and goes back to where it was started.
+func (m model) clearSearch() model {
+	m.searching = false
+	m.search = ""
+	m.searchInput = ""
+	m.currentFile = m.searchReturn
+	m.refreshPreview()
+	return m
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// session is the review state saved with --save-session and picked up again
+// with --resume.
+type session struct {
+	Root    string     `json:"root"`
+	SavedAt time.Time  `json:"saved_at"`
+	Files   []FileItem `json:"files"`
+}
+
+func loadSession(path, dir string) (*session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("reading session %s: %w", path, err)
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if s.Root != root {
+		return nil, fmt.Errorf("session %s was saved for %s, not %s", path, s.Root, root)
+	}
+	return &s, nil
+}
+
+// saveSession writes the model's decisions to path. The file is replaced
+// atomically so an interrupted save never loses the previous session.
+func (m model) saveSession(path string) error {
+	root, err := filepath.Abs(m.cfg.Dir)
+	if err != nil {
+		return err
+	}
+	s := session{Root: root, SavedAt: time.Now(), Files: m.files}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// applySession carries the saved decisions over to the freshly scanned
+// files. Saved entries that no longer exist are dropped by virtue of not
+// being scanned; entries whose size or modification time changed since are
+// reviewed again. There is no saved position: review simply continues at the
+// first file still needing a decision, which also catches new files.
+func (m *model) applySession(s *session) {
+	saved := make(map[string]FileItem, len(s.Files))
+	for _, file := range s.Files {
+		saved[file.Path] = file
+	}
+
+	for i, file := range m.files {
+		old, ok := saved[file.Path]
+		if !ok || old.Size != file.Size || !old.ModTime.Equal(file.ModTime) {
+			continue
+		}
+		if old.Decided {
+			m.decide(i, old.Keep)
+			m.files[i].Move = old.Move
+			m.files[i].LinkBack = old.LinkBack
+		}
+		m.files[i].Skipped = old.Skipped
+	}
+}
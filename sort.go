@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// junkWeights tunes how much size, age and file type contribute to a file's
+// junk score. Set with --junk-weights "size=1,age=2,type=1".
+type junkWeights struct {
+	Size float64
+	Age  float64
+	Type float64
+}
+
+var defaultJunkWeights = junkWeights{Size: 1, Age: 1, Type: 1}
+
+func (w *junkWeights) String() string {
+	return fmt.Sprintf("size=%g,age=%g,type=%g", w.Size, w.Age, w.Type)
+}
+
+func (w *junkWeights) Set(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		key, raw, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return fmt.Errorf("junk weight %q must look like name=value", part)
+		}
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid junk weight %q", part)
+		}
+		switch key {
+		case "size":
+			w.Size = n
+		case "age":
+			w.Age = n
+		case "type":
+			w.Type = n
+		default:
+			return fmt.Errorf("unknown junk weight %q (want size, age or type)", key)
+		}
+	}
+	return nil
+}
+
+// junkTypeScore rates how disposable a file looks from its name alone.
+func junkTypeScore(path string) float64 {
+	name := strings.ToLower(filepath.Base(path))
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tmp", ".temp", ".cache", ".log", ".bak", ".old", ".swp", ".part", ".crdownload", ".dmp":
+		return 1
+	}
+	if strings.HasSuffix(name, "~") || name == ".ds_store" || name == "thumbs.db" {
+		return 1
+	}
+	switch fileCategory(path) {
+	case "archives", "packages":
+		return 0.5
+	}
+	return 0
+}
+
+// junkScore combines size, age and type into a single number; large, old,
+// temp-looking files score highest. Each component is roughly 0..1.
+func junkScore(file FileItem, w junkWeights, now time.Time) float64 {
+	size := math.Log10(float64(file.Size)+1) / 10         // ~1 at 10 GB
+	age := min(now.Sub(file.ModTime).Hours()/24/365/3, 1) // saturates at 3 years
+	return w.Size*size + w.Age*max(age, 0) + w.Type*junkTypeScore(file.Path)
+}
+
+// suggestThreshold is the junk score from which --suggest hints that a file
+// can go: an old temp file easily passes it, and so does a big download
+// nobody touched for a couple of years.
+const suggestThreshold = 1.5
+
+// suggestDeletion reports whether --suggest should hint that file looks
+// deletable, and why. Symlinks never are, as their size and age say
+// nothing about what they point to.
+func suggestDeletion(file FileItem, w junkWeights, now time.Time) (bool, []string) {
+	if file.IsSymlink || junkScore(file, w, now) < suggestThreshold {
+		return false, nil
+	}
+	var reasons []string
+	if now.Sub(file.ModTime) > 365*24*time.Hour {
+		reasons = append(reasons, "untouched for over a year")
+	}
+	if file.Size >= 100<<20 {
+		reasons = append(reasons, "large")
+	}
+	if junkTypeScore(file.Path) >= 1 {
+		reasons = append(reasons, "temporary file type")
+	}
+	return true, reasons
+}
+
+// sortFiles orders the review queue. An empty mode keeps scan order.
+func sortFiles(files []FileItem, mode string, weights junkWeights) {
+	switch mode {
+	case "junk":
+		now := time.Now()
+		scores := make(map[string]float64, len(files))
+		for _, file := range files {
+			scores[file.Path] = junkScore(file, weights, now)
+		}
+		sort.SliceStable(files, func(i, j int) bool {
+			return scores[files[i].Path] > scores[files[j].Path]
+		})
+	case "size":
+		sort.SliceStable(files, func(i, j int) bool { return files[i].Size < files[j].Size })
+	case "size-desc":
+		sort.SliceStable(files, func(i, j int) bool { return files[i].Size > files[j].Size })
+	case "date":
+		sort.SliceStable(files, func(i, j int) bool { return files[i].ModTime.Before(files[j].ModTime) })
+	case "date-desc":
+		sort.SliceStable(files, func(i, j int) bool { return files[i].ModTime.After(files[j].ModTime) })
+	case "name":
+		sort.SliceStable(files, func(i, j int) bool {
+			return strings.ToLower(files[i].Name) < strings.ToLower(files[j].Name)
+		})
+	}
+}
+
+func validSortMode(mode string) bool {
+	switch mode {
+	case "", "junk", "size", "size-desc", "date", "date-desc", "name":
+		return true
+	}
+	return false
+}
+
+// Age buckets, oldest first. --by-age reviews them in this order.
+var ageBuckets = []string{"Older than a year", "This year", "This month", "This week", "Today"}
+
+func ageBucket(modTime, now time.Time) int {
+	age := now.Sub(modTime)
+	switch {
+	case age < 24*time.Hour:
+		return 4
+	case age < 7*24*time.Hour:
+		return 3
+	case age < 30*24*time.Hour:
+		return 2
+	case age < 365*24*time.Hour:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// groupByAge stably reorders files into age buckets, oldest bucket first,
+// keeping any previous ordering within each bucket.
+func groupByAge(files []FileItem, now time.Time) {
+	sort.SliceStable(files, func(i, j int) bool {
+		return ageBucket(files[i].ModTime, now) < ageBucket(files[j].ModTime, now)
+	})
+}
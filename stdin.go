@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// readPathList reads newline-separated paths, as printed by find or fd.
+// Paths that don't exist are reported through warn and left out, as are
+// blank lines and repeats.
+func readPathList(r io.Reader, warn func(string)) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		path := filepath.Clean(line)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if _, err := os.Lstat(path); err != nil {
+			warn(fmt.Sprintf("skipping %s: %v", path, err))
+			continue
+		}
+		paths = append(paths, path)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading paths from stdin: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no existing paths on stdin")
+	}
+	return paths, nil
+}
+
+// scanPaths builds review items for exactly the given paths, without
+// walking or filtering anything.
+func scanPaths(paths []string, cfg Config, fn func(FileItem), onErr func(scanError)) error {
+	for _, path := range paths {
+		info, err := os.Lstat(path)
+		if err != nil {
+			// Gone since it was read from stdin
+			onErr(scanError{Path: path, Err: err})
+			continue
+		}
+		item := newFileItem(path, info, cfg)
+		if item.IsDir {
+			item.DirFiles, item.Size = dirStats(path)
+		}
+		fn(item)
+	}
+	return nil
+}
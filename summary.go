@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// summaryView is the overview shown between scanning and review with
+// --summary: how many files of each type were found and how big they are.
+func (m model) summaryView() string {
+	counts := make(map[string]int)
+	sizes := make(map[string]int64)
+	var total int64
+	for _, file := range m.files {
+		category := itemCategory(file)
+		counts[category]++
+		sizes[category] += file.Size
+		total += file.Size
+	}
+
+	lines := []string{fmt.Sprintf("%s files, %s total", formatCount(len(m.files)), formatSize(total)), ""}
+	for _, category := range categoryOrder {
+		if counts[category] == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%-10s %8s  %10s", category, formatCount(counts[category]), formatSize(sizes[category])))
+	}
+
+	card := m.cardStyle(false).Render(strings.Join(lines, "\n"))
+	return fmt.Sprintf("\n%s\n\n%s\n\nPress any key to start reviewing | q=quit",
+		titleStyle.Render("Summary"), card)
+}
+
+func (m model) handleSummaryInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.cfg.Keys.action(msg.String()) == actionQuit {
+		return m, tea.Quit
+	}
+	return m.startReview()
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Emoji variation selector. Terminals draw "⚙️" as a two-cell emoji while
+// runewidth (and therefore lipgloss) counts it as one, which shifts borders.
+const variationSelector16 = "\ufe0f"
+
+// normalizeIcon strips emoji presentation selectors so the rendered width of
+// an icon matches what the layout code measures.
+func normalizeIcon(icon string) string {
+	return strings.ReplaceAll(icon, variationSelector16, "")
+}
+
+// sanitizeName replaces control characters in file names, which would
+// otherwise move the cursor and tear the surrounding box apart.
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return '?'
+		}
+		return r
+	}, name)
+}
+
+// fitPath shortens a path to the given display width, keeping its tail so the
+// file name stays visible. Wide (CJK) runes are measured as two cells.
+func fitPath(path string, width int) string {
+	path = sanitizeName(path)
+	if width <= 0 || runewidth.StringWidth(path) <= width {
+		return path
+	}
+	return runewidth.TruncateLeft(path, runewidth.StringWidth(path)-width+1, "…")
+}
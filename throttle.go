@@ -0,0 +1,41 @@
+package main
+
+import (
+	"time"
+)
+
+// throttle is a small token bucket used to pace filesystem operations on
+// slow or shared mounts. A nil throttle never waits.
+type throttle struct {
+	rate   float64 // tokens added per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newThrottle(perSecond float64) *throttle {
+	if perSecond <= 0 {
+		return nil
+	}
+	burst := max(1, perSecond/10)
+	return &throttle{rate: perSecond, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// Wait blocks until one more operation is allowed.
+func (t *throttle) Wait() {
+	if t == nil {
+		return
+	}
+
+	now := time.Now()
+	t.tokens = min(t.burst, t.tokens+now.Sub(t.last).Seconds()*t.rate)
+	t.last = now
+
+	if t.tokens < 1 {
+		wait := time.Duration((1 - t.tokens) / t.rate * float64(time.Second))
+		time.Sleep(wait)
+		t.last = t.last.Add(wait)
+		t.tokens = 1
+	}
+	t.tokens--
+}
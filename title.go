@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/muesli/termenv"
+)
+
+// windowTitle is what the terminal title should say right now, so progress
+// is visible from a background tab.
+func (m model) windowTitle() string {
+	switch m.screen {
+	case ScreenReview:
+		if m.currentFile < len(m.files) {
+			return fmt.Sprintf("dinder — %d/%d", m.currentFile+1, len(m.files))
+		}
+	case ScreenConfirm:
+		return "dinder — confirm"
+	case ScreenProgress:
+		return fmt.Sprintf("dinder — deleting %d/%d", m.progress, m.maxProgress)
+	case ScreenComplete:
+		return "dinder — done"
+	}
+	return "dinder"
+}
+
+// titleSequence is the escape sequence that sets the terminal title, sent
+// as part of the view unless --no-title is set.
+func (m model) titleSequence() string {
+	if m.cfg.NoTitle {
+		return ""
+	}
+	return termenv.OSC + "2;" + m.windowTitle() + "\a"
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// restoreTarget makes sure original can receive a restored file: nothing may
+// have taken its place and its parent directory has to exist again.
+func restoreTarget(original string) error {
+	if _, err := os.Lstat(original); err == nil {
+		return fmt.Errorf("%s already exists", original)
+	}
+	return os.MkdirAll(filepath.Dir(original), 0o755)
+}
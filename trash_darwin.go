@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// moveToTrash moves path into ~/.Trash and returns its new location.
+func moveToTrash(path string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	trash := filepath.Join(home, ".Trash")
+	if err := os.MkdirAll(trash, 0o700); err != nil {
+		return "", err
+	}
+
+	dst := freePath(filepath.Join(trash, filepath.Base(path)))
+	if err := os.Rename(path, dst); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+// restoreFromTrash moves a trashed file back to original.
+func restoreFromTrash(location, original string) error {
+	if err := restoreTarget(original); err != nil {
+		return err
+	}
+	return os.Rename(location, original)
+}
@@ -0,0 +1,13 @@
+//go:build !unix && !windows
+
+package main
+
+import "errors"
+
+func moveToTrash(path string) (string, error) {
+	return "", errors.New("no trash on this platform; use --permanent")
+}
+
+func restoreFromTrash(location, original string) error {
+	return errors.New("no trash on this platform")
+}
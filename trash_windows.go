@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+var procSHFileOperation = syscall.NewLazyDLL("shell32.dll").NewProc("SHFileOperationW")
+
+// shFileOpStruct mirrors SHFILEOPSTRUCTW.
+type shFileOpStruct struct {
+	hwnd                  uintptr
+	wFunc                 uint32
+	pFrom                 *uint16
+	pTo                   *uint16
+	fFlags                uint16
+	fAnyOperationsAborted int32
+	hNameMappings         uintptr
+	lpszProgressTitle     *uint16
+}
+
+const (
+	foDelete          = 0x0003
+	fofSilent         = 0x0004
+	fofNoConfirmation = 0x0010
+	fofAllowUndo      = 0x0040
+	fofNoErrorUI      = 0x0400
+)
+
+// moveToTrash sends path to the Recycle Bin. Windows doesn't tell us where
+// the file ended up, so the returned location is empty.
+func moveToTrash(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	// pFrom must be terminated by two NULs
+	from, err := syscall.UTF16FromString(abs)
+	if err != nil {
+		return "", err
+	}
+	from = append(from, 0)
+
+	op := shFileOpStruct{
+		wFunc:  foDelete,
+		pFrom:  &from[0],
+		fFlags: fofAllowUndo | fofNoConfirmation | fofSilent | fofNoErrorUI,
+	}
+	ret, _, _ := procSHFileOperation.Call(uintptr(unsafe.Pointer(&op)))
+	if ret != 0 {
+		return "", fmt.Errorf("moving %s to the Recycle Bin failed (code %d)", path, ret)
+	}
+	if op.fAnyOperationsAborted != 0 {
+		return "", fmt.Errorf("moving %s to the Recycle Bin was aborted", path)
+	}
+	return "", nil
+}
+
+func restoreFromTrash(location, original string) error {
+	return errors.New("restoring from the Recycle Bin is not supported")
+}
@@ -0,0 +1,164 @@
+//go:build unix && !darwin
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// xdgTrashDir returns the home trash described by the freedesktop.org
+// trash specification.
+func xdgTrashDir() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "Trash"), nil
+}
+
+// moveToTrash moves path into the XDG trash, writing the .trashinfo record
+// file managers use to restore it. It returns the file's new location.
+//
+// Files on another filesystem than the home trash go to the .Trash-$UID
+// directory at the top of their own mount instead, as the spec describes,
+// since moving them home would mean copying.
+func moveToTrash(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	trash, err := xdgTrashDir()
+	if err != nil {
+		return "", err
+	}
+	if sameFilesystem(abs, trash) {
+		return trashInto(trash, abs, abs)
+	}
+
+	top, err := mountPoint(abs)
+	if err != nil {
+		return "", err
+	}
+	// Paths in a mount's own trash are recorded relative to the mount, so
+	// they still restore if it is mounted somewhere else
+	rel, err := filepath.Rel(top, abs)
+	if err != nil {
+		return "", err
+	}
+	return trashInto(filepath.Join(top, fmt.Sprintf(".Trash-%d", os.Getuid())), abs, rel)
+}
+
+// trashInto moves abs into the trash directory trash, recording original as
+// the path to restore it to.
+func trashInto(trash, abs, original string) (string, error) {
+	filesDir := filepath.Join(trash, "files")
+	infoDir := filepath.Join(trash, "info")
+	if err := os.MkdirAll(filesDir, 0o700); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(infoDir, 0o700); err != nil {
+		return "", err
+	}
+
+	// Reserve a unique name by creating its info file exclusively. Names
+	// taken in files/ without an info file, left behind by other tools,
+	// are skipped too so the rename can't replace them
+	name := filepath.Base(abs)
+	var infoPath string
+	for i := 0; ; i++ {
+		candidate := name
+		if i > 0 {
+			ext := filepath.Ext(name)
+			candidate = fmt.Sprintf("%s.%d%s", strings.TrimSuffix(name, ext), i, ext)
+		}
+		if _, err := os.Lstat(filepath.Join(filesDir, candidate)); err == nil {
+			continue
+		}
+		infoPath = filepath.Join(infoDir, candidate+".trashinfo")
+		info, err := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = fmt.Fprintf(info, "[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+			escapeTrashPath(original), time.Now().Format("2006-01-02T15:04:05"))
+		if cerr := info.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(infoPath)
+			return "", err
+		}
+		name = candidate
+		break
+	}
+
+	dst := filepath.Join(filesDir, name)
+	if err := os.Rename(abs, dst); err != nil {
+		os.Remove(infoPath)
+		return "", err
+	}
+	return dst, nil
+}
+
+// mountPoint returns the top directory of the filesystem path is on: the
+// last parent still on the same device.
+func mountPoint(path string) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", fmt.Errorf("can't tell which filesystem %s is on", path)
+	}
+
+	dir := filepath.Dir(path)
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir, nil
+		}
+		pinfo, err := os.Stat(parent)
+		if err != nil {
+			return "", err
+		}
+		if pst, ok := pinfo.Sys().(*syscall.Stat_t); !ok || pst.Dev != st.Dev {
+			return dir, nil
+		}
+		dir = parent
+	}
+}
+
+// escapeTrashPath percent-encodes a path the way the spec asks for, keeping
+// the slashes readable.
+func escapeTrashPath(path string) string {
+	return (&url.URL{Path: path}).EscapedPath()
+}
+
+// restoreFromTrash moves a trashed file back to original and drops its
+// .trashinfo record.
+func restoreFromTrash(location, original string) error {
+	if err := restoreTarget(original); err != nil {
+		return err
+	}
+	if err := os.Rename(location, original); err != nil {
+		return err
+	}
+	trash := filepath.Dir(filepath.Dir(location))
+	os.Remove(filepath.Join(trash, "info", filepath.Base(location)+".trashinfo"))
+	return nil
+}
@@ -0,0 +1,40 @@
+//go:build unix && !darwin
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTrashIntoSkipsOrphanedNames(t *testing.T) {
+	trash := t.TempDir()
+	// Trashed by another tool that never wrote the info file
+	orphan := filepath.Join(trash, "files", "notes.txt")
+	if err := os.MkdirAll(filepath.Dir(orphan), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(orphan, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	src := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(src, []byte("new"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	dst, err := trashInto(trash, src, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := filepath.Join(trash, "files", "notes.1.txt"); dst != want {
+		t.Errorf("trashed to %s, want %s", dst, want)
+	}
+	if data, err := os.ReadFile(orphan); err != nil || string(data) != "old" {
+		t.Errorf("orphaned trash file was replaced: %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(trash, "info", "notes.1.txt.trashinfo")); err != nil {
+		t.Errorf("info file missing: %v", err)
+	}
+}
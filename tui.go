@@ -5,42 +5,145 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/alecthomas/chroma/v2/formatters"
-	"github.com/alecthomas/chroma/v2/lexers"
 	"github.com/alecthomas/chroma/v2/styles"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/fsnotify/fsnotify"
 )
 
 type Screen int
 
 const (
 	ScreenLoading Screen = iota
+	ScreenSummary
 	ScreenReview
+	ScreenList
 	ScreenConfirm
 	ScreenProgress
+	ScreenEmptyDirs
 	ScreenComplete
 )
 
 type model struct {
-	screen       Screen
-	files        []FileItem
-	currentFile  int
-	toDelete     []FileItem
-	toSkip       []FileItem
-	spinner      int
-	progress     int
-	maxProgress  int
-	totalSize    int64
-	deletedSize  int64
-	err          error
-}
-
-type filesLoadedMsg []FileItem
-type deletionCompleteMsg struct{}
+	screen          Screen
+	files           []FileItem
+	currentFile     int
+	toDelete        []FileItem
+	toSkip          []FileItem
+	toOrganize      []organizeMove
+	deleteProblems  map[string]string
+	organized       int
+	organizeFailed  int
+	spinner         int
+	progress        int
+	maxProgress     int
+	totalSize       int64
+	markedSize      int64
+	deletedSize     int64
+	deletedCount    int
+	trashed         []trashedFile
+	restoreErrs     []deleteFailure
+	limiter         *throttle
+	cfg             Config
+	watcher         *fsnotify.Watcher
+	watchFilter     *scanFilter
+	placed          graphicPlacement
+	zoomDir         string
+	zoomReturn      int
+	searching       bool
+	searchInput     string
+	search          string
+	searchReturn    int
+	notice          string
+	dirSummaries    map[string]dirSummary
+	auditPath       string
+	auditErr        error
+	rawPreview      bool
+	confirmOffset   int
+	showHelp        bool
+	failures        []deleteFailure
+	seenBuckets     map[int]bool
+	seenGroups      map[string]bool
+	hashing         bool
+	scanErrors      []scanError
+	secondPass      bool
+	emptyDirs       []string
+	emptyRemoved    int
+	scanStart       time.Time
+	scanTook        time.Duration
+	scannedCount    int
+	deleteStart     time.Time
+	deleteTook      time.Duration
+	scanBatches     <-chan scanBatchMsg
+	scanning        bool
+	scannedSize     int64
+	session         *session
+	estimate        int
+	width           int
+	height          int
+	err             error
+	confirmEach     bool
+	confirmEachAt   int
+	listCursor      int
+	listOffset      int
+	listSelected    map[int]bool
+	alreadyGone     []string
+	wouldDelete     []string
+	quitPrompt      bool
+	undoStack       []undoRecord
+	redoStack       []undoRecord
+	fullPreview     []string
+	fullPreviewPath string
+	fullPreviewAt   int
+	fullPreviewCut  bool
+	thumbnails      map[string]bool
+	bells           *int
+}
+
+type dirSummariesMsg map[string]dirSummary
+
+// fileDeletedMsg reports one finished entry of m.toDelete so the progress
+// screen can advance while the rest are still being removed.
+type fileDeletedMsg struct {
+	index   int
+	freed   int64
+	trashed string
+	gone    bool
+	failure *deleteFailure
+}
+
+// trashedFile remembers where a deleted file went so the Complete screen can
+// put it back.
+type trashedFile struct {
+	File     FileItem
+	Location string
+}
+
+type restoredMsg struct {
+	restored []trashedFile
+	failures []deleteFailure
+}
+
+type deletionCompleteMsg struct {
+	failures       []deleteFailure
+	deleted        int
+	freed          int64
+	organized      int
+	organizeFailed int
+}
+
+// deleteFailure records a removal that did not fully succeed. For
+// directories, Remaining counts the files still left behind.
+type deleteFailure struct {
+	File      FileItem
+	Err       error
+	Remaining int
+}
 type tickMsg time.Time
 
 var (
@@ -62,11 +165,11 @@ var (
 			Width(70)
 
 	codePreviewStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#F39C12")).
-			Padding(1, 2).
-			Width(80).
-			Height(12)
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(lipgloss.Color("#F39C12")).
+				Padding(1, 2).
+				Width(80).
+				Height(12)
 
 	buttonStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FFF7DB")).
@@ -78,7 +181,20 @@ var (
 			Background(lipgloss.Color("#04B575"))
 
 	deleteButtonStyle = buttonStyle.Copy().
-			Background(lipgloss.Color("#FF5F56"))
+				Background(lipgloss.Color("#FF5F56"))
+
+	warningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#F39C12")).
+			Bold(true)
+
+	suggestColor = lipgloss.Color("#FF5F56")
+
+	suggestStyle = lipgloss.NewStyle().
+			Foreground(suggestColor).
+			Bold(true)
+
+	disabledHintStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#626262"))
 
 	progressStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#7D56F4"))
@@ -86,17 +202,24 @@ var (
 	spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 )
 
-func initialModel() model {
+func initialModel(cfg Config) model {
 	return model{
-		screen:  ScreenLoading,
-		spinner: 0,
+		screen:       ScreenLoading,
+		spinner:      0,
+		cfg:          cfg,
+		seenBuckets:  make(map[int]bool),
+		seenGroups:   make(map[string]bool),
+		listSelected: make(map[int]bool),
+		scanning:     true,
+		scanStart:    time.Now(),
 	}
 }
 
 func (m model) Init() tea.Cmd {
 	return tea.Batch(
 		tick(),
-		loadFiles,
+		estimateFiles(m.cfg.Dir, m.cfg),
+		startScan(m.cfg.Dir, m.cfg),
 	)
 }
 
@@ -106,48 +229,169 @@ func tick() tea.Cmd {
 	})
 }
 
-func loadFiles() tea.Msg {
-	files, err := scanDirectory(".")
-	if err != nil {
-		return err
-	}
-	return filesLoadedMsg(files)
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	next, cmd = next.(model).requestThumbnail(cmd)
+	return next.(model).placeGraphic(cmd)
 }
 
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.showHelp {
+			switch msg.String() {
+			case "?", "esc":
+				m.showHelp = false
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		if msg.String() == "?" && !m.searching {
+			m.showHelp = true
+			return m, nil
+		}
+
 		switch m.screen {
+		case ScreenSummary:
+			return m.handleSummaryInput(msg)
+		case ScreenList:
+			return m.handleListInput(msg)
 		case ScreenReview:
 			return m.handleReviewInput(msg)
 		case ScreenConfirm:
 			return m.handleConfirmInput(msg)
+		case ScreenEmptyDirs:
+			return m.handleEmptyDirsInput(msg)
 		case ScreenComplete:
 			if msg.String() == "q" || msg.String() == "ctrl+c" {
 				return m, tea.Quit
 			}
+			if msg.String() == "u" && m.canRestore() {
+				return m, m.restoreFiles()
+			}
 		}
-		
+
 		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
 		}
 
-	case filesLoadedMsg:
-		m.files = []FileItem(msg)
-		if len(m.files) == 0 {
-			m.screen = ScreenComplete
-		} else {
-			m.screen = ScreenReview
+	case thumbnailMsg:
+		return m.handleThumbnail(msg)
+
+	case editorFinishedMsg:
+		return m.handleEditorFinished(msg)
+
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case scanStartedMsg:
+		m.scanBatches = msg.batches
+		return m, waitForScan(m.scanBatches)
+
+	case scanEstimateMsg:
+		if m.scanning {
+			m.estimate = int(msg)
 		}
 		return m, nil
 
+	case scanBatchMsg:
+		return m.handleScanBatch(msg)
+
+	case watcherReadyMsg:
+		m.watcher = msg.watcher
+		m.watchFilter = msg.filter
+		return m, waitForChange(m.watcher, m.watchFilter, m.cfg)
+
+	case fileAddedMsg, fileChangedMsg, fileRemovedMsg, watchIgnoredMsg, watchErrorMsg:
+		return m.handleWatchEvent(msg)
+
+	case dirSummariesMsg:
+		if m.dirSummaries == nil {
+			m.dirSummaries = make(map[string]dirSummary)
+		}
+		for dir, summary := range msg {
+			m.dirSummaries[dir] = summary
+		}
+		if m.screen == ScreenConfirm {
+			m.prepareConfirmation()
+		}
+		return m, nil
+
+	case auditWrittenMsg:
+		m.auditPath = msg.path
+		m.auditErr = msg.err
+		m.screen = ScreenComplete
+		return m, nil
+
+	case fileDeletedMsg:
+		m.progress = msg.index + 1
+		if msg.failure != nil {
+			m.failures = append(m.failures, *msg.failure)
+		} else if msg.gone {
+			m.alreadyGone = append(m.alreadyGone, m.toDelete[msg.index].Path)
+		} else {
+			m.deletedCount++
+			m.deletedSize += msg.freed
+			m.ringBell(1)
+			if m.cfg.DryRun {
+				// Nothing was touched; the report lists it as would_delete
+				m.wouldDelete = append(m.wouldDelete, m.toDelete[msg.index].Path)
+			} else if i := m.indexOfFile(m.toDelete[msg.index].Path); i >= 0 {
+				m.files[i].Deleted = true
+			}
+			if msg.trashed != "" {
+				m.trashed = append(m.trashed, trashedFile{File: m.toDelete[msg.index], Location: msg.trashed})
+			}
+		}
+		if next := msg.index + 1; next < len(m.toDelete) {
+			return m, m.deleteFile(next)
+		}
+		return m, m.organizeFiles()
+
+	case restoredMsg:
+		return m.handleRestored(msg)
+
 	case deletionCompleteMsg:
+		m.deleteTook = time.Since(m.deleteStart)
+		m.failures = append(m.failures, msg.failures...)
+		m.deletedCount += msg.deleted
+		m.deletedSize += msg.freed
+		m.organized = msg.organized
+		m.organizeFailed = msg.organizeFailed
+		if m.deletedCount > 0 && !m.cfg.DryRun && m.cfg.Archive == "" {
+			return m, m.findEmptyDirs()
+		}
+		m.screen = ScreenComplete
+		m.ringBell(completeBells)
+		return m, nil
+
+	case emptyDirsMsg:
+		// Rung with the last screen of the run: a frame that is replaced
+		// before the terminal gets to see it takes its bells with it
+		m.ringBell(completeBells)
+		m.emptyDirs = msg
+		m.screen = ScreenEmptyDirs
+		if len(msg) == 0 {
+			m.screen = ScreenComplete
+		}
+		return m, nil
+
+	case emptyDirsRemovedMsg:
+		m.emptyRemoved = msg.removed
+		m.failures = append(m.failures, msg.failures...)
+		m.emptyDirs = nil
 		m.screen = ScreenComplete
 		return m, nil
 
 	case tickMsg:
-		if m.screen == ScreenLoading || m.screen == ScreenProgress {
-			m.spinner = (m.spinner + 1) % len(spinnerFrames)
+		if m.screen == ScreenLoading || m.screen == ScreenProgress || m.scanning {
+			m.spinner = nextSpinner(m.spinner, len(spinnerFrames))
 			return m, tick()
 		}
 
@@ -160,137 +404,847 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) handleReviewInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "right", "l", "y":
-		m.files[m.currentFile].Keep = true
-		m.files[m.currentFile].Decided = true
+	if m.currentFile >= len(m.files) {
+		// Watch mode with an empty queue: nothing to decide on yet
+		if m.cfg.Keys.action(msg.String()) == actionQuit {
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	if m.fullPreview != nil {
+		return m.handleFullPreviewInput(msg)
+	}
+	if m.searching {
+		return m.handleSearchInput(msg)
+	}
+	m.notice = ""
+
+	if category, ok := m.groupHeader(); ok {
+		return m.handleGroupInput(category, msg)
+	}
+	if bucket, ok := m.bucketHeader(); ok && m.cfg.Keys.action(msg.String()) != actionQuit {
+		m.seenBuckets[bucket] = true
+		return m, nil
+	}
+
+	return m.reviewAction(m.cfg.Keys.action(msg.String()))
+}
+
+// reviewAction performs a review action: one of the bindable actions, or a
+// fixed key such as "m" or "z". Keys and button clicks both end up here.
+func (m model) reviewAction(action string) (tea.Model, tea.Cmd) {
+	switch action {
+	case actionKeep:
+		m.beginUndo()
+		m.remember(m.currentFile)
+		m.decide(m.currentFile, true)
 		return m.nextFile()
-	case "left", "h", "n":
-		m.files[m.currentFile].Keep = false
-		m.files[m.currentFile].Decided = true
+	case actionDelete:
+		if !m.canDelete(m.files[m.currentFile]) {
+			return m, nil
+		}
+		m.beginUndo()
+		m.remember(m.currentFile)
+		m.decide(m.currentFile, false)
 		return m.nextFile()
-	case "s":
+	case actionSkip:
+		m.beginUndo()
+		m.remember(m.currentFile)
+		if m.secondPass {
+			// Skipping twice keeps the file, so the review always ends
+			m.decide(m.currentFile, true)
+			return m.nextFile()
+		}
 		m.files[m.currentFile].Skipped = true
 		return m.nextFile()
-	case "u":
-		if m.currentFile > 0 {
-			m.currentFile--
-			m.files[m.currentFile].Decided = false
-			m.files[m.currentFile].Skipped = false
+	case "m", "M":
+		if m.cfg.MoveTo == "" || m.cfg.Archive != "" {
+			return m, nil
 		}
+		m.beginUndo()
+		m.remember(m.currentFile)
+		m.decide(m.currentFile, true)
+		file := &m.files[m.currentFile]
+		file.Move = true
+		file.LinkBack = action == "M"
+		return m.nextFile()
+	case "D", "K":
+		return m.decideRemaining(action == "K")
+	case "o":
+		return m.keepOnlyCopy()
+	case "P":
+		if m.cfg.Archive != "" {
+			return m, nil
+		}
+		if err := protectPath(m.cfg.KeepFile, m.files[m.currentFile].Path); err != nil {
+			m.notice = fmt.Sprintf("Could not protect file: %v", err)
+			return m, nil
+		}
+		// Undo brings the decision back, but the file stays protected
+		m.beginUndo()
+		m.remember(m.currentFile)
+		m.decide(m.currentFile, true)
+		return m.nextFile()
+	case "e":
+		return m.openInEditor()
+	case "z":
+		return m.toggleZoom()
+	case "/":
+		return m.startSearch()
+	case "esc":
+		if m.search != "" {
+			return m.clearSearch(), nil
+		}
+	case " ":
+		return m.openFullPreview()
+	case "v":
+		m.rawPreview = !m.rawPreview
 		return m, nil
-	case "q":
+	case "+", "=":
+		m.cfg.PreviewLines = min(m.cfg.PreviewLines+5, maxPreviewLines)
+		m.refreshPreview()
+		return m, nil
+	case "-":
+		m.cfg.PreviewLines = max(m.cfg.PreviewLines-5, minPreviewLines)
+		return m, nil
+	case "p":
+		if file := &m.files[m.currentFile]; file.PreviewTooLarge && m.cfg.Archive == "" {
+			if isImageFile(file.Path) {
+				file.PreviewTooLarge = false
+				return m, loadThumbnail(file.Path)
+			}
+			file.Preview, file.TotalLines, file.MoreLines = getFilePreview(file.Path, m.cfg.PreviewLines)
+			file.PreviewLines = m.cfg.PreviewLines
+			file.PreviewTooLarge = false
+		}
+		return m, nil
+	case actionUndo:
+		return m.undo()
+	case "ctrl+r":
+		return m.redo()
+	case actionQuit:
 		return m, tea.Quit
 	}
 	return m, nil
 }
 
+// decide records a keep or delete decision for m.files[i], keeping the
+// marked-for-deletion tally in step.
+func (m *model) decide(i int, keep bool) {
+	m.undecide(i)
+	m.files[i].Keep = keep
+	m.files[i].Decided = true
+	if !keep {
+		m.markedSize += m.files[i].Size
+	}
+}
+
+func (m *model) undecide(i int) {
+	if file := m.files[i]; file.Decided && !file.Keep {
+		m.markedSize -= file.Size
+	}
+	m.files[i].Decided = false
+}
+
+// decideRemaining keeps or deletes every undecided file from the current one
+// onward and goes straight to confirmation, where the bulk decision can
+// still be reviewed before anything happens.
+func (m model) decideRemaining(keep bool) (tea.Model, tea.Cmd) {
+	m.beginUndo()
+	for i := m.currentFile; i < len(m.files); i++ {
+		file := m.files[i]
+		if file.Decided || file.Skipped || (!keep && !m.canDelete(file)) {
+			continue
+		}
+		m.remember(i)
+		m.decide(i, keep)
+	}
+	m.currentFile = len(m.files)
+	m.zoomDir, m.search = "", ""
+	m.prepareConfirmation()
+	m.screen = ScreenConfirm
+	return m, m.summarizeDirs()
+}
+
 func (m model) handleConfirmInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirmEach {
+		return m.handleConfirmEachInput(msg)
+	}
+	if m.quitPrompt {
+		return m.handleQuitPrompt(msg)
+	}
+	switch msg.String() {
+	case "enter":
+		// Deliberately not "y": during review y means keep, and a reflexive
+		// press here must never start a deletion.
+		if m.cfg.InteractiveConfirm && len(m.toDelete) > 0 {
+			return m.startConfirmEach()
+		}
+		return m.startDeletion()
+	case "q":
+		if m.cfg.SaveSession == "" && m.hasDecisions() {
+			m.quitPrompt = true
+			return m, nil
+		}
+		return m, tea.Quit
+	case "n":
+		return m, tea.Quit
+	case "up", "k":
+		return m.scrollConfirm(-1), nil
+	case "down", "j":
+		return m.scrollConfirm(1), nil
+	case "pgup":
+		return m.scrollConfirm(-m.confirmListHeight()), nil
+	case "pgdown":
+		return m.scrollConfirm(m.confirmListHeight()), nil
+	case "r":
+		if m.pendingFiles() > 0 {
+			m.screen = ScreenReview
+		}
+	case "esc":
+		if m.cfg.List {
+			m.screen = ScreenList
+		}
+	}
+	return m, nil
+}
+
+// defaultSessionFile is where decisions go when saved from the quit prompt
+// without --save-session.
+const defaultSessionFile = "dinder-session.json"
+
+// handleQuitPrompt answers "Save decisions before quitting?". Saving itself
+// happens in main once the program has exited, like --save-session.
+func (m model) handleQuitPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "y":
-		m.screen = ScreenProgress
-		m.maxProgress = len(m.toDelete)
-		return m, tea.Batch(tick(), m.deleteFiles())
+		m.cfg.SaveSession = defaultSessionFile
+		return m, tea.Quit
 	case "n", "q":
 		return m, tea.Quit
+	case "esc":
+		m.quitPrompt = false
 	}
 	return m, nil
 }
 
+// hasDecisions reports whether any file was decided, which a quit would
+// throw away.
+func (m model) hasDecisions() bool {
+	for _, file := range m.files {
+		if file.Decided {
+			return true
+		}
+	}
+	return false
+}
+
+// revisitSkipped puts skipped files back into the queue for a final
+// decision, reporting whether there were any.
+func (m *model) revisitSkipped() bool {
+	found := false
+	for i := range m.files {
+		if m.files[i].Skipped && !m.files[i].Decided {
+			// Part of the action that finished the first pass, so undoing
+			// it goes back to before the second pass
+			m.remember(i)
+			m.files[i].Skipped = false
+			found = true
+		}
+	}
+	return found
+}
+
+// startDeletion carries out the confirmed decisions.
+func (m model) startDeletion() (tea.Model, tea.Cmd) {
+	if m.cfg.Audit {
+		// Audit runs never delete; confirming only writes the report
+		return m, m.writeAudit()
+	}
+	m.screen = ScreenProgress
+	m.deleteStart = time.Now()
+	m.progress = 0
+	m.maxProgress = len(m.toDelete)
+	m.limiter = newThrottle(m.cfg.Throttle)
+	m.failures = nil
+	m.alreadyGone = nil
+	m.wouldDelete = nil
+	m.trashed = nil
+	m.restoreErrs = nil
+	return m, tea.Batch(tick(), m.deleteFiles())
+}
+
+// statusBar tallies the decisions made so far, e.g.
+// "Kept: 12 | Marked for deletion: 30 (1.2 GB) | Skipped: 3".
+func (m model) statusBar() string {
+	counts := map[string]int{}
+	for _, file := range m.files {
+		counts[decisionOf(file)]++
+	}
+	return fmt.Sprintf("Kept: %d | Marked for deletion: %d (%s) | Skipped: %d",
+		counts["keep"], counts["delete"], formatSize(m.markedSize), counts["skip"])
+}
+
+// timingSummary reports how long the scan and the deletion took, e.g.
+// "Scanned 4,210 files in 1.2s, deleted 312 in 0.4s".
+func (m model) timingSummary() string {
+	summary := fmt.Sprintf("Scanned %s files in %s", formatCount(m.scannedCount), formatDuration(m.scanTook))
+	if !m.deleteStart.IsZero() {
+		summary += fmt.Sprintf(", deleted %s in %s", formatCount(m.deletedCount), formatDuration(m.deleteTook))
+	}
+	return summary
+}
+
+// formatDuration rounds d to something readable: milliseconds below a
+// second, tenths of a second above.
+func formatDuration(d time.Duration) string {
+	if d < time.Millisecond {
+		return "<1ms"
+	}
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// pendingFiles counts files that arrived through the watcher after the
+// review queue was exhausted.
+func (m model) pendingFiles() int {
+	return max(len(m.files)-m.currentFile, 0)
+}
+
 func (m model) nextFile() (tea.Model, tea.Cmd) {
 	for {
 		m.currentFile++
 		if m.currentFile >= len(m.files) {
+			if m.scanning && m.zoomDir == "" && m.search == "" {
+				// Caught up with the scanner; wait for the next batch
+				return m, nil
+			}
+			if m.zoomDir != "" {
+				// Folder done, pick the full queue back up where we left it
+				m.zoomDir = ""
+				m.currentFile = m.zoomReturn - 1
+				continue
+			}
+			if m.search != "" {
+				// All matches handled, back to the full queue
+				m.search = ""
+				m.currentFile = m.searchReturn - 1
+				continue
+			}
+			if !m.secondPass && m.revisitSkipped() {
+				m.secondPass = true
+				m.currentFile = -1
+				continue
+			}
 			m.prepareConfirmation()
+			if m.cfg.Yes {
+				// Only files the user explicitly marked are in toDelete
+				return m.startDeletion()
+			}
 			m.screen = ScreenConfirm
-			break
+			return m, m.summarizeDirs()
+		}
+		file := m.files[m.currentFile]
+		if file.Skipped || file.Decided {
+			continue
 		}
-		if !m.files[m.currentFile].Skipped {
-			break
+		if m.zoomDir != "" && filepath.Dir(file.Path) != m.zoomDir {
+			continue
 		}
+		if m.search != "" && !matchesSearch(file, m.search) {
+			continue
+		}
+		break
+	}
+	m.refreshPreview()
+	return m, nil
+}
+
+// Bounds for adjusting the preview length with +/- during review.
+const (
+	minPreviewLines = 3
+	maxPreviewLines = 200
+)
+
+// refreshPreview loads image thumbnails on demand and re-reads the current
+// file's preview when more lines are wanted than it was loaded with. Fewer
+// lines are simply cut at render time.
+func (m *model) refreshPreview() {
+	if m.currentFile >= len(m.files) {
+		return
+	}
+	file := &m.files[m.currentFile]
+	if m.cfg.Archive != "" || m.cfg.NoPreview || file.IsDir {
+		return
+	}
+	if isImageFile(file.Path) {
+		// Thumbnails are decoded in the background by requestThumbnail
+		return
+	}
+	if file.Preview == "" || file.PreviewLines >= m.cfg.PreviewLines {
+		return
+	}
+	file.Preview, file.TotalLines, file.MoreLines = getFilePreview(file.Path, m.cfg.PreviewLines)
+	file.PreviewLines = m.cfg.PreviewLines
+}
+
+// toggleZoom narrows the queue to the current file's directory, or restores
+// the full queue when already zoomed in.
+func (m model) toggleZoom() (tea.Model, tea.Cmd) {
+	if m.zoomDir != "" {
+		m.zoomDir = ""
+		m.currentFile = m.zoomReturn - 1
+		return m.nextFile()
 	}
+	m.zoomDir = filepath.Dir(m.files[m.currentFile].Path)
+	m.zoomReturn = m.currentFile
 	return m, nil
 }
 
+func (m model) zoomProgress() (int, int) {
+	done, total := 0, 0
+	for _, file := range m.files {
+		if filepath.Dir(file.Path) != m.zoomDir {
+			continue
+		}
+		total++
+		if file.Decided || file.Skipped {
+			done++
+		}
+	}
+	return done, total
+}
+
+// deleteSize is the space removing file frees, counting everything inside
+// directories once their summaries are known.
+func (m model) deleteSize(file FileItem) int64 {
+	if summary, ok := m.dirSummaries[file.Path]; ok {
+		return summary.Size
+	}
+	return file.Size
+}
+
 func (m *model) prepareConfirmation() {
 	m.toDelete = []FileItem{}
 	m.toSkip = []FileItem{}
+	m.toOrganize = []organizeMove{}
 	m.totalSize = 0
-	
+	m.confirmOffset = 0
+	m.deleteProblems = map[string]string{}
+
 	for _, file := range m.files {
-		if file.Decided && !file.Keep {
+		if file.Deleted {
+			continue
+		}
+		if file.Decided && !file.Keep && m.canDelete(file) {
 			m.toDelete = append(m.toDelete, file)
-			m.totalSize += file.Size
+			m.totalSize += m.deleteSize(file)
+			if problem := deleteProblem(file); problem != "" && m.cfg.Archive == "" {
+				m.deleteProblems[file.Path] = problem
+			}
 		} else if file.Skipped {
 			m.toSkip = append(m.toSkip, file)
+		} else if file.Decided && file.Keep && file.Move {
+			m.toOrganize = append(m.toOrganize, organizeMove{
+				File:         file,
+				Dest:         m.cfg.MoveTo,
+				Link:         file.LinkBack,
+				CrossesMount: !sameFilesystem(file.Path, m.cfg.MoveTo),
+			})
+		} else if file.Decided && file.Keep {
+			if dest := m.cfg.OrganizeKept.destination(file); dest != "" && m.cfg.Archive == "" {
+				m.toOrganize = append(m.toOrganize, organizeMove{File: file, Dest: dest})
+			}
+		}
+	}
+}
+
+// canDelete reports whether the file may be marked for deletion at all.
+func (m model) canDelete(file FileItem) bool {
+	return !(file.IsDir && m.cfg.NoDirDelete)
+}
+
+// trimArchive "deletes" entries from the reviewed archive by writing it back
+// without them.
+func (m model) trimArchive() tea.Cmd {
+	remove := make(map[string]bool, len(m.toDelete))
+	for _, file := range m.toDelete {
+		remove[file.Path] = true
+	}
+	archive := m.cfg.Archive
+	count, size := len(m.toDelete), m.totalSize
+
+	dryRun := m.cfg.DryRun
+
+	return func() tea.Msg {
+		var result deletionCompleteMsg
+		if dryRun {
+			result.deleted, result.freed = count, size
+			return result
 		}
+		if err := rewriteArchive(archive, remove); err != nil {
+			result.failures = append(result.failures, deleteFailure{
+				File: FileItem{Path: archive, Name: filepath.Base(archive)},
+				Err:  err,
+			})
+		} else {
+			result.deleted, result.freed = count, size
+		}
+		return result
 	}
 }
 
+// summarizeDirs looks inside every directory marked for deletion so the
+// confirmation screen can show what is about to go with it.
+func (m model) summarizeDirs() tea.Cmd {
+	var dirs []string
+	for _, file := range m.toDelete {
+		if _, done := m.dirSummaries[file.Path]; file.IsDir && !done {
+			dirs = append(dirs, file.Path)
+		}
+	}
+	if len(dirs) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		summaries := make(dirSummariesMsg)
+		for _, dir := range dirs {
+			summaries[dir] = summarizeDir(dir, 3)
+		}
+		return summaries
+	}
+}
+
+// deleteFiles starts the deletion chain. Each entry is removed by its own
+// command so the progress screen updates between files; kept-file moves run
+// once the last deletion has been reported.
 func (m model) deleteFiles() tea.Cmd {
+	if m.cfg.Archive != "" {
+		return m.trimArchive()
+	}
+	if len(m.toDelete) == 0 {
+		return m.organizeFiles()
+	}
+	return m.deleteFile(0)
+}
+
+func (m model) deleteFile(index int) tea.Cmd {
+	file := m.toDelete[index]
+	freed := m.deleteSize(file)
+	return func() tea.Msg {
+		m.limiter.Wait()
+		msg := fileDeletedMsg{index: index, freed: freed}
+		if _, err := os.Lstat(file.Path); os.IsNotExist(err) {
+			// Removed by something else since it was reviewed; its size
+			// must not count as freed
+			msg.freed, msg.gone = 0, true
+			return msg
+		}
+		if m.cfg.DryRun {
+			return msg
+		}
+		if !m.cfg.Permanent {
+			location, err := moveToTrash(file.Path)
+			if err != nil {
+				msg.failure = &deleteFailure{File: file, Err: err}
+			}
+			msg.trashed = location
+			return msg
+		}
+		if err := os.RemoveAll(file.Path); err != nil {
+			msg.failure = &deleteFailure{
+				File:      file,
+				Err:       err,
+				Remaining: countRemaining(file.Path),
+			}
+		}
+		return msg
+	}
+}
+
+// canRestore reports whether the files from the last deletion can still be
+// taken back out of the trash.
+func (m model) canRestore() bool {
+	return len(m.trashed) > 0
+}
+
+// restoreFiles moves everything trashed by the last confirmation back to
+// where it came from.
+func (m model) restoreFiles() tea.Cmd {
+	trashed := m.trashed
+	return func() tea.Msg {
+		var result restoredMsg
+		for _, t := range trashed {
+			err := restoreFromTrash(t.Location, t.File.Path)
+			if err != nil {
+				result.failures = append(result.failures, deleteFailure{File: t.File, Err: err})
+				continue
+			}
+			result.restored = append(result.restored, t)
+		}
+		return result
+	}
+}
+
+// handleRestored puts restored files back into the queue as undecided and
+// returns to the review screen at the first of them.
+func (m model) handleRestored(msg restoredMsg) (tea.Model, tea.Cmd) {
+	m.trashed = nil
+	m.restoreErrs = msg.failures
+	first := -1
+	for _, t := range msg.restored {
+		m.deletedCount--
+		m.deletedSize -= m.deleteSize(t.File)
+		i := m.indexOfFile(t.File.Path)
+		if i < 0 {
+			continue
+		}
+		m.files[i].Deleted = false
+		m.undecide(i)
+		m.files[i].Keep = false
+		if first < 0 || i < first {
+			first = i
+		}
+	}
+	if first < 0 {
+		return m, nil
+	}
+	m.currentFile = first
+	m.screen = ScreenReview
+	m.refreshPreview()
+	return m, nil
+}
+
+func (m model) organizeFiles() tea.Cmd {
 	return func() tea.Msg {
-		for _, file := range m.toDelete {
-			os.RemoveAll(file.Path)
+		var result deletionCompleteMsg
+		if m.cfg.DryRun {
+			result.organized = len(m.toOrganize)
+			return result
+		}
+		for _, move := range m.toOrganize {
+			m.limiter.Wait()
+			dst, err := moveFile(move.File.Path, move.Dest)
+			if err == nil && move.Link {
+				err = linkBack(dst, move.File.Path)
+			}
+			if err != nil {
+				result.organizeFailed++
+			} else {
+				result.organized++
+			}
 		}
-		return deletionCompleteMsg{}
+		return result
 	}
 }
 
 func (m model) View() string {
+	view := m.view()
+	if !strings.HasPrefix(view, "\n") {
+		return view
+	}
+	// Sequences for the terminal itself ride along on the blank first line,
+	// so they reach it through bubbletea's renderer between whole frames
+	return m.titleSequence() + m.bellSequence() + m.graphicSequence() + view
+}
+
+func (m model) view() string {
+	if m.showHelp {
+		return m.helpView()
+	}
+
 	switch m.screen {
 	case ScreenLoading:
-		return fmt.Sprintf("\n%s Loading files...\n", spinnerFrames[m.spinner])
+		found := fmt.Sprintf("%d files, %s", len(m.files), formatSize(m.scannedSize))
+		if n := len(m.cfg.Exclude); n > 0 {
+			found += fmt.Sprintf(", %d exclude patterns loaded", n)
+		}
+		if n := len(m.scanErrors); n > 0 {
+			found += fmt.Sprintf(", %d unreadable paths skipped", n)
+		}
+		if m.hashing {
+			return fmt.Sprintf("\n%s Looking for duplicates among %s...\n", m.spinnerFrame(), found)
+		}
+		if m.estimate > 0 {
+			return fmt.Sprintf("\n%s Loading files... %s (~%d expected)\n", m.spinnerFrame(), found, m.estimate)
+		}
+		return fmt.Sprintf("\n%s Loading files... %s\n", m.spinnerFrame(), found)
 
 	case ScreenReview:
 		if m.currentFile >= len(m.files) {
+			if m.scanning {
+				return fmt.Sprintf("\n%s\n\n%s Scanning for more files...\n\nPress q to quit",
+					titleStyle.Render("File Review"), m.spinnerFrame())
+			}
+			if m.cfg.Watch {
+				waiting := "Watching for new files..."
+				if m.notice != "" {
+					waiting += "\n" + warningStyle.Render("⚠ "+m.notice)
+				}
+				return fmt.Sprintf("\n%s\n\n%s\n\nPress q to quit",
+					titleStyle.Render("File Review"), waiting)
+			}
 			return "No more files to review"
 		}
-		
+
+		if m.fullPreview != nil {
+			return m.fullPreviewView()
+		}
+		if bucket, ok := m.bucketHeader(); ok {
+			return m.bucketHeaderView(bucket)
+		}
+		if category, ok := m.groupHeader(); ok {
+			return m.groupHeaderView(category)
+		}
+
 		file := m.files[m.currentFile]
 		fileType := "FILE"
-		icon := getFileIcon(file.Path, file.IsDir)
+		icon := itemIcon(file)
 		if file.IsDir {
 			fileType = "DIR"
 		}
-		
+		if file.IsSymlink {
+			fileType = "SYMLINK"
+		}
+
 		sizeStr := formatSize(file.Size)
 		dateStr := file.ModTime.Format("2006-01-02 15:04")
-		
-		content := fmt.Sprintf("%s %s\n%s\n\nSize: %s\nModified: %s", 
-			icon, fileType, file.Path, sizeStr, dateStr)
-		
+
+		isCode := isCodeFile(file.Path) && !file.IsDir
+		boxStyle := m.cardStyle(file.Preview != "" && isCode)
+		pathWidth := boxStyle.GetWidth() - boxStyle.GetHorizontalPadding()
+
+		content := fmt.Sprintf("%s %s\n%s\n\nSize: %s\nModified: %s\nMode: %s",
+			icon, fileType, fitPath(m.displayPath(file.Path), pathWidth), sizeStr, dateStr, file.Mode)
+		if file.Owner != "" {
+			content += fmt.Sprintf("\nOwner: %s:%s", file.Owner, file.Group)
+		}
+		if file.Root != "" {
+			content += "\nFrom: " + fitPath(file.Root, pathWidth-6)
+		}
+		if file.ReadOnly {
+			content += "\n" + warningStyle.Render("⚠ not writable by you; deleting it will likely fail")
+		}
+		if file.IsDir {
+			content += fmt.Sprintf("\nContains: %s files", formatCount(file.DirFiles))
+		}
+		if file.IsSymlink {
+			content += fmt.Sprintf("\nPoints to: %s (only the link is deleted)", sanitizeName(file.LinkTarget))
+		}
+		if dupes := m.duplicatesOf(m.currentFile); len(dupes) > 0 {
+			content += fmt.Sprintf("\nDuplicates: %d other copies (o = keep only this one)", len(dupes))
+			for _, i := range dupes[:min(len(dupes), 3)] {
+				content += "\n  = " + fitPath(m.displayPath(m.files[i].Path), pathWidth-4)
+			}
+			if len(dupes) > 3 {
+				content += fmt.Sprintf("\n  ...and %d more", len(dupes)-3)
+			}
+		}
+
+		if file.Suggested {
+			// The border makes guesses easy to spot while flicking through
+			boxStyle = boxStyle.Copy().BorderForeground(suggestColor)
+			hint := "💡 suggested for deletion"
+			if _, reasons := suggestDeletion(file, m.cfg.JunkWeights, time.Now()); len(reasons) > 0 {
+				hint += ": " + strings.Join(reasons, ", ")
+			}
+			content += "\n" + suggestStyle.Render(hint)
+		}
+
+		if !m.canDelete(file) {
+			content += "\n\n🔒 directory deletion disabled"
+		}
+
 		var fileBox string
 		var codeBox string
-		
+
 		if file.Preview != "" {
-			if isCodeFile(file.Path) {
+			if isCode {
 				// File info box (no preview mixed in)
-				fileBox = codeFileStyle.Render(content)
-				
-				// Separate code preview box
-				highlightedPreview := applySyntaxHighlighting(file.Preview, file.Path)
-				codeContent := fmt.Sprintf("Code Preview:\n\n%s", highlightedPreview)
-				codeBox = codePreviewStyle.Render(codeContent)
+				fileBox = boxStyle.Render(content)
+
+				// Separate code preview box, sized to the terminal
+				width, height := m.previewSize()
+				preview := limitLines(file.Preview, min(m.cfg.PreviewLines, height-4))
+				renderedPreview := renderPreview(preview, file, m.cfg.Theme, m.rawPreview)
+				codeContent := fmt.Sprintf("%s\n\n%s", codePreviewHeader(file, m.rawPreview), renderedPreview)
+				codeBox = codePreviewStyle.Copy().
+					Width(width).
+					Height(height).
+					MaxHeight(height + 2).
+					Render(codeContent)
 			} else {
-				content += "\n\nPreview:\n" + file.Preview
-				fileBox = fileStyle.Render(content)
+				label := "Preview:"
+				if m.rawPreview {
+					label = "Preview (raw):"
+				}
+				content += "\n\n" + label + "\n" + renderPreview(file.Preview, file, m.cfg.Theme, m.rawPreview)
+				fileBox = boxStyle.Render(content)
 			}
 		} else {
-			fileBox = fileStyle.Render(content)
-		}
-		
-		keepBtn := keepButtonStyle.Render("✓ Keep (→/l/y)")
-		deleteBtn := deleteButtonStyle.Render("✗ Delete (←/h/n)")
-		skipBtn := buttonStyle.Render("↷ Skip (s)")
-		
-		buttons := lipgloss.JoinHorizontal(lipgloss.Top, keepBtn, "  ", deleteBtn, "  ", skipBtn)
-		
+			if file.PreviewTooLarge {
+				content += fmt.Sprintf("\n\nFile too large to preview (%s) — press p to preview anyway",
+					formatSize(file.Size))
+			}
+			fileBox = boxStyle.Render(content)
+		}
+
+		buttons := joinButtons(m.reviewButtons(file))
+
 		progress := fmt.Sprintf("Progress: %d/%d", m.currentFile+1, len(m.files))
-		controls := "Controls: u=undo last | q=quit"
-		
+		if m.scanning {
+			progress += fmt.Sprintf(" (~%d files expected, still scanning)", max(m.estimate, len(m.files)))
+		}
+		if m.cfg.Archive != "" {
+			progress += fmt.Sprintf(" | Archive: %s", fitPath(m.cfg.Archive, 40))
+		}
+		if m.zoomDir != "" {
+			done, total := m.zoomProgress()
+			progress += fmt.Sprintf(" | Folder %s: %d/%d", fitPath(m.zoomDir, 40), done+1, total)
+		}
+		if m.search != "" {
+			progress += fmt.Sprintf(" | Search %q", m.search)
+		}
+		if n := len(m.cfg.Exclude); n > 0 {
+			progress += fmt.Sprintf(" | %d exclude patterns", n)
+		}
+		if n := len(m.scanErrors); n > 0 {
+			progress += fmt.Sprintf(" | %d unreadable paths skipped", n)
+		}
+		if m.secondPass {
+			progress += " | Second look at skipped files (skipping again keeps)"
+		}
+		progress += "\n" + m.statusBar()
+		if m.notice != "" {
+			progress += "\n" + warningStyle.Render("⚠ "+m.notice)
+		}
+		zoomHint := "z=focus folder"
+		if m.zoomDir != "" {
+			zoomHint = "z=back to full queue"
+		}
+		controls := fmt.Sprintf("Controls: %s=undo last | space=full preview | v=raw/rendered preview | +/- preview lines | %s | /=search | ?=help | %s=quit",
+			m.cfg.Keys.label(actionUndo), zoomHint, m.cfg.Keys.label(actionQuit))
+		for _, warning := range m.cfg.KeyWarnings {
+			controls += "\n⚠ " + warning
+		}
+		if m.search != "" {
+			controls += " | esc=end search"
+		}
+		if m.searching {
+			controls = fmt.Sprintf("/%s█  %d matches — enter to review them, esc to cancel",
+				sanitizeName(m.searchInput), m.searchMatches())
+		}
+
 		// Layout with two boxes for code files
 		if codeBox != "" {
 			topSection := lipgloss.JoinHorizontal(lipgloss.Top, fileBox, "  ", codeBox)
+			if m.stackPreview() {
+				topSection = lipgloss.JoinVertical(lipgloss.Left, fileBox, codeBox)
+			}
 			return fmt.Sprintf("\n%s\n\n%s\n\n%s\n\n%s\n%s",
 				titleStyle.Render("File Review"),
 				topSection,
@@ -310,56 +1264,491 @@ func (m model) View() string {
 		}
 
 	case ScreenConfirm:
-		if len(m.toDelete) == 0 {
+		if m.confirmEach {
+			return m.confirmEachView()
+		}
+		if m.quitPrompt {
+			prompt := lipgloss.JoinHorizontal(lipgloss.Top,
+				keepButtonStyle.Render("Save (y)"), "  ", buttonStyle.Render("Don't save (n)"))
+			return fmt.Sprintf("\n%s\n\nSave decisions before quitting?\nThey go to %s; continue later with --resume %s.\n\n%s\n\nesc=back",
+				titleStyle.Render("Quit"), defaultSessionFile, defaultSessionFile, prompt)
+		}
+		if m.cfg.Audit {
+			return m.auditConfirmView()
+		}
+		if len(m.toDelete) == 0 && len(m.toOrganize) == 0 {
 			skippedInfo := ""
 			if len(m.toSkip) > 0 {
 				skippedInfo = fmt.Sprintf("\n%d files skipped for later review.", len(m.toSkip))
 			}
-			return "\n" + titleStyle.Render("Complete") + "\n\nNo files selected for deletion." + skippedInfo + "\n\nPress q to quit"
+			return "\n" + titleStyle.Render("Complete") + "\n\nNo files selected for deletion." + skippedInfo + m.scanErrorsInfo() + m.pendingInfo() + "\n\nPress q to quit"
 		}
-		
-		var deleteList strings.Builder
-		for _, file := range m.toDelete {
-			icon := getFileIcon(file.Path, file.IsDir)
-			deleteList.WriteString(fmt.Sprintf("  %s %s (%s)\n", icon, file.Path, formatSize(file.Size)))
-		}
-		
+
 		sizeInfo := fmt.Sprintf("Total size: %s", formatSize(m.totalSize))
 		skippedInfo := ""
 		if len(m.toSkip) > 0 {
 			skippedInfo = fmt.Sprintf("\n%d files skipped.", len(m.toSkip))
 		}
-		
-		return fmt.Sprintf("\n%s\n\nFiles to delete (%d):\n%s\n%s%s\n\nConfirm deletion? (y/n)",
+		if n := len(m.deleteProblems); n > 0 {
+			skippedInfo += "\n" + warningStyle.Render(fmt.Sprintf("⚠ %d of these will likely fail to delete (see above)", n))
+		}
+
+		action := "✗ Move to trash (enter)"
+		if m.cfg.Permanent {
+			action = "✗ Delete permanently (enter)"
+		}
+		if m.cfg.Archive != "" {
+			action = "✗ Rewrite archive (enter)"
+		}
+		if m.cfg.DryRun {
+			action = "✗ Dry run (enter)"
+		}
+		prompt := lipgloss.JoinHorizontal(lipgloss.Top,
+			deleteButtonStyle.Render(action), "  ", buttonStyle.Render("Cancel (n/q)"))
+
+		return fmt.Sprintf("\n%s\n\n%s\n%s%s%s\n\n%s",
 			titleStyle.Render("Confirmation"),
-			len(m.toDelete),
-			deleteList.String(),
+			m.confirmListView(),
 			sizeInfo,
 			skippedInfo,
+			m.pendingInfo(),
+			prompt,
 		)
 
 	case ScreenProgress:
-		bar := progressStyle.Render(fmt.Sprintf("%s Deleting files... %d/%d", 
-			spinnerFrames[m.spinner], m.progress, m.maxProgress))
-		return fmt.Sprintf("\n%s\n\n%s", titleStyle.Render("Progress"), bar)
+		status := progressStyle.Render(fmt.Sprintf("%s Deleting files... %d/%d",
+			m.spinnerFrame(), m.progress, m.maxProgress))
+		width := 40
+		if m.width > 0 {
+			width = max(m.width-8, 10)
+		}
+		return fmt.Sprintf("\n%s\n\n%s\n\n%s", titleStyle.Render("Progress"), status,
+			progressGauge(m.progress, m.maxProgress, width))
+
+	case ScreenSummary:
+		return m.summaryView()
+
+	case ScreenList:
+		return m.listView()
+
+	case ScreenEmptyDirs:
+		return m.emptyDirsView()
 
 	case ScreenComplete:
-		stats := fmt.Sprintf("Files deleted: %d\nSpace freed: %s", 
-			len(m.toDelete), formatSize(m.totalSize))
-		
+		if m.cfg.Audit {
+			return m.auditCompleteView()
+		}
+		verb := "trashed"
+		if m.cfg.Permanent || m.cfg.Archive != "" {
+			verb = "deleted"
+		}
+		stats := fmt.Sprintf("Files %s: %d\nSpace freed: %s",
+			verb, m.deletedCount, formatSize(m.deletedSize))
+		if m.cfg.DryRun {
+			stats = fmt.Sprintf("Files that would be deleted: %d\nSpace that would be freed: %s",
+				m.deletedCount, formatSize(m.deletedSize))
+		}
+		if m.emptyRemoved > 0 {
+			stats += fmt.Sprintf("\nEmpty folders removed: %d", m.emptyRemoved)
+		}
+		if byExt := m.removedByExt(); byExt != "" {
+			stats += "\nBy type: " + byExt
+		}
+		if n := len(m.alreadyGone); n > 0 {
+			stats += fmt.Sprintf("\n%d files already removed by something else", n)
+		}
+		if len(m.failures) > 0 {
+			stats += fmt.Sprintf("\nFailed to delete: %d", len(m.failures))
+		}
+		if m.organized > 0 || m.organizeFailed > 0 {
+			stats += fmt.Sprintf("\nKept files moved: %d", m.organized)
+			if m.organizeFailed > 0 {
+				stats += fmt.Sprintf(" (%d could not be moved)", m.organizeFailed)
+			}
+		}
+		stats += "\n" + m.timingSummary()
+
 		skippedInfo := ""
 		if len(m.toSkip) > 0 {
 			skippedInfo = fmt.Sprintf("\n%d files were skipped.", len(m.toSkip))
 		}
-		
-		return fmt.Sprintf("\n%s\n\nDeletion complete!\n\n%s%s\n\nPress q to quit",
-			titleStyle.Render("Complete"), stats, skippedInfo)
 
+		headline := "Deletion complete!"
+		if m.cfg.DryRun {
+			headline = "DRY RUN — no files were deleted"
+		}
+		if len(m.failures) > 0 {
+			headline = fmt.Sprintf("Deletion finished with %d problems:\n%s", len(m.failures), formatFailures(m.failures))
+		}
+
+		if len(m.restoreErrs) > 0 {
+			headline += fmt.Sprintf("\n\n%d files could not be restored:", len(m.restoreErrs))
+			for _, f := range m.restoreErrs {
+				headline += fmt.Sprintf("\n  ⚠ %s: %v", sanitizeName(f.File.Path), f.Err)
+			}
+		}
+
+		hint := "Press u to restore the deleted files, q to quit"
+		if m.cfg.DryRun {
+			hint = "Press q to quit and print what would have been deleted"
+		} else if !m.canRestore() {
+			hint = disabledHintStyle.Render("u: restore unavailable") + "\nPress q to quit"
+		}
+
+		return fmt.Sprintf("\n%s\n\n%s\n\n%s%s\n\n%s",
+			titleStyle.Render("Complete"), headline, stats, skippedInfo+m.scanErrorsInfo(), hint)
+
+	}
+
+	return ""
+}
+
+// manyFilesWarning is the file count above which a directory marked for
+// deletion gets flagged on the confirmation screen.
+const manyFilesWarning = 50
+
+// confirmLines is the scrollable part of the confirmation screen: what will
+// be deleted and which kept files will be moved.
+func (m model) confirmLines() []string {
+	lines := []string{fmt.Sprintf("Files to delete (%d):", len(m.toDelete))}
+	for _, file := range m.toDelete {
+		entry := m.deleteLines(file)
+		if age := time.Since(file.ModTime); age < recentlyModified {
+			// Likely work in progress; make it stand out
+			entry[0] = warningStyle.Render(entry[0])
+			entry = append(entry, warningStyle.Render("      ⚠ modified "+formatAgo(age)))
+		}
+		lines = append(lines, entry...)
+		if problem, ok := m.deleteProblems[file.Path]; ok {
+			lines = append(lines, warningStyle.Render("      ⚠ will likely fail: "+problem))
+		}
+	}
+	return append(lines, m.organizeLines()...)
+}
+
+// recentlyModified is how fresh a file marked for deletion has to be for
+// the confirmation screen to call it out.
+const recentlyModified = time.Hour
+
+// formatAgo renders a short age such as "just now" or "12 minutes ago".
+func formatAgo(age time.Duration) string {
+	switch minutes := int(age.Minutes()); {
+	case minutes < 1:
+		return "just now"
+	case minutes == 1:
+		return "1 minute ago"
+	default:
+		return fmt.Sprintf("%d minutes ago", minutes)
+	}
+}
+
+func (m model) deleteLines(file FileItem) []string {
+	icon := itemIcon(file)
+	if file.IsSymlink {
+		return []string{fmt.Sprintf("  %s %s → %s (link only)", icon, sanitizeName(file.Path), sanitizeName(file.LinkTarget))}
+	}
+	if !file.IsDir {
+		return []string{fmt.Sprintf("  %s %s (%s)", icon, sanitizeName(file.Path), formatSize(file.Size))}
+	}
+	// The summary is fresher, but the scan's numbers do until it's in
+	summary, ok := m.dirSummaries[file.Path]
+	if !ok {
+		summary = dirSummary{Files: file.DirFiles, Size: file.Size}
+	}
+	lines := []string{fmt.Sprintf("  %s %s (%s in %d files)",
+		icon, sanitizeName(file.Path), formatSize(summary.Size), summary.Files)}
+	if summary.Files > manyFilesWarning {
+		lines = append(lines, fmt.Sprintf("      ⚠ contains %s files", formatCount(summary.Files)))
+	}
+	if len(summary.Largest) > 0 {
+		lines = append(lines, "      contains: "+formatDirSample(summary))
+	}
+	return lines
+}
+
+func (m model) organizeLines() []string {
+	var lines []string
+	if len(m.toOrganize) > 0 {
+		lines = append(lines, "", fmt.Sprintf("Kept files to move (%d):", len(m.toOrganize)))
+	}
+	for _, move := range m.toOrganize {
+		icon := itemIcon(move.File)
+		line := fmt.Sprintf("  %s %s → %s", icon, sanitizeName(move.File.Path), sanitizeName(move.Dest))
+		if move.Link {
+			line += " (symlink left behind)"
+		}
+		lines = append(lines, line)
+		if move.Link && move.CrossesMount {
+			lines = append(lines, "      ⚠ moves to another filesystem; the symlink will break if it is unmounted")
+		}
+	}
+	return lines
+}
+
+// confirmListHeight is how many list lines fit above the total and the
+// prompt, which always stay visible.
+func (m model) confirmListHeight() int {
+	if m.height == 0 {
+		return len(m.confirmLines())
+	}
+	return max(m.height-12, 3)
+}
+
+// scrollConfirm moves the confirmation list by delta lines, keeping it
+// within bounds.
+func (m model) scrollConfirm(delta int) model {
+	maxOffset := max(len(m.confirmLines())-m.confirmListHeight(), 0)
+	m.confirmOffset = min(max(m.confirmOffset+delta, 0), maxOffset)
+	return m
+}
+
+func (m model) confirmListView() string {
+	lines := m.confirmLines()
+	height := m.confirmListHeight()
+	if len(lines) <= height {
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	offset := min(m.confirmOffset, len(lines)-height)
+	visible := lines[offset : offset+height]
+	below := len(lines) - offset - height
+	return fmt.Sprintf("%s\n%s\n", strings.Join(visible, "\n"),
+		disabledHintStyle.Render(fmt.Sprintf("  ↑ %d more above, ↓ %d more below (↑/↓, pgup/pgdn to scroll)", offset, below)))
+}
+
+func (m model) auditConfirmView() string {
+	var summary strings.Builder
+	counts := map[string]int{}
+	for _, file := range m.files {
+		counts[decisionOf(file)]++
+	}
+	for _, decision := range []string{"delete", "keep", "skip", "undecided"} {
+		summary.WriteString(fmt.Sprintf("  %-10s %d\n", decision, counts[decision]))
+	}
+
+	return fmt.Sprintf("\n%s\n\nAUDIT MODE — nothing will be deleted.\n\nDecisions:\n%s\nMarked for deletion: %s\n\nWrite audit report to %s? (enter = write, n/q = quit)",
+		titleStyle.Render("Audit"),
+		summary.String(),
+		formatSize(m.totalSize),
+		m.cfg.AuditReport,
+	)
+}
+
+func (m model) auditCompleteView() string {
+	result := fmt.Sprintf("Report written to %s", m.auditPath)
+	if m.auditErr != nil {
+		result = fmt.Sprintf("Could not write report: %v", m.auditErr)
+	}
+	return fmt.Sprintf("\n%s\n\nAudit complete — no files were or will be deleted.\n\n%s\n\nPress q to quit",
+		titleStyle.Render("Complete"), result)
+}
+
+// scanErrorsInfo lists the first few paths the scan couldn't read, for the
+// completion screen.
+func (m model) scanErrorsInfo() string {
+	if len(m.scanErrors) == 0 {
+		return ""
+	}
+	const shown = 5
+	info := fmt.Sprintf("\n\n%d paths could not be read and were not reviewed:", len(m.scanErrors))
+	for _, e := range m.scanErrors[:min(len(m.scanErrors), shown)] {
+		info += fmt.Sprintf("\n  ⚠ %s: %v", sanitizeName(e.Path), e.Err)
+	}
+	if more := len(m.scanErrors) - shown; more > 0 {
+		info += fmt.Sprintf("\n  ...and %d more", more)
+	}
+	return info
+}
+
+func formatFailures(failures []deleteFailure) string {
+	var b strings.Builder
+	for _, f := range failures {
+		path := sanitizeName(f.File.Path)
+		switch {
+		case f.File.IsDir && f.Remaining > 0:
+			b.WriteString(fmt.Sprintf("  ⚠ directory %s partially deleted — %d files could not be removed\n", path, f.Remaining))
+		default:
+			b.WriteString(fmt.Sprintf("  ⚠ %s could not be deleted: %v\n", path, f.Err))
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func formatDirSample(summary dirSummary) string {
+	parts := make([]string, len(summary.Largest))
+	for i, file := range summary.Largest {
+		parts[i] = fmt.Sprintf("%s (%s)", sanitizeName(file.Name), formatSize(file.Size))
+	}
+	sample := strings.Join(parts, ", ")
+	if more := summary.Files - len(summary.Largest); more > 0 {
+		sample += fmt.Sprintf(", ...and %d more", more)
+	}
+	return sample
+}
+
+// bucketHeader reports whether the current file opens an age bucket whose
+// header card has not been shown yet.
+func (m model) bucketHeader() (int, bool) {
+	if !m.cfg.ByAge || m.currentFile >= len(m.files) {
+		return 0, false
+	}
+	bucket := ageBucket(m.files[m.currentFile].ModTime, time.Now())
+	return bucket, !m.seenBuckets[bucket]
+}
+
+func (m model) bucketHeaderView(bucket int) string {
+	count, size := 0, int64(0)
+	now := time.Now()
+	for _, file := range m.files[m.currentFile:] {
+		if ageBucket(file.ModTime, now) == bucket && !file.Decided {
+			count++
+			size += file.Size
+		}
+	}
+
+	card := m.cardStyle(false).Render(fmt.Sprintf("🗓  %s\n\n%d files, %s", ageBuckets[bucket], count, formatSize(size)))
+	return fmt.Sprintf("\n%s\n\n%s\n\nPress any key to start reviewing this group | q=quit",
+		titleStyle.Render("File Review"), card)
+}
+
+// displayPath shows a path relative to the reviewed directory so deeply
+// nested files don't carry the whole root along.
+func (m model) displayPath(path string) string {
+	if m.cfg.Archive != "" {
+		return path
+	}
+	for _, root := range m.cfg.Dirs {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			if len(m.cfg.Dirs) > 1 {
+				// Relative paths alone could belong to any of the roots
+				return filepath.Join(filepath.Base(root), rel)
+			}
+			return rel
+		}
+	}
+	return path
+}
+
+// spinnerFrame returns the current spinner glyph. It tolerates an empty or
+// replaced frame set instead of indexing out of range.
+func (m model) spinnerFrame() string {
+	if len(spinnerFrames) == 0 {
+		return ""
+	}
+	return spinnerFrames[m.spinner%len(spinnerFrames)]
+}
+
+func nextSpinner(current, frames int) int {
+	if frames == 0 {
+		return 0
 	}
+	return (current + 1) % frames
+}
 
+func (m model) pendingInfo() string {
+	if n := m.pendingFiles(); n > 0 {
+		return fmt.Sprintf("\n%d new files arrived. Press r to review them.", n)
+	}
 	return ""
 }
 
+// previewSize returns the code preview box dimensions. Explicit flags win;
+// otherwise the width fills the space next to the file info box and the
+// height follows from the configured ratio.
+func (m model) previewSize() (int, int) {
+	width := m.cfg.PreviewWidth
+	if width == 0 {
+		width = 80
+		if m.stackPreview() {
+			width = m.width - 2
+		} else if m.width > 0 {
+			// Whatever the info card and the borders leave
+			width = m.width - m.cardStyle(true).GetWidth() - 6
+		}
+	}
+	width = max(width, 20)
+
+	height := m.cfg.PreviewHeight
+	if height == 0 {
+		// Grow past the ratio when more lines were asked for with +
+		height = max(int(float64(width)/m.cfg.PreviewRatio), m.cfg.PreviewLines+4)
+		if m.stackPreview() {
+			// The info card sits above the preview now
+			height = min(height, m.height-26)
+		} else if m.height > 0 {
+			height = min(height, m.height-12)
+		}
+	}
+	height = max(height, 5)
+
+	return width, height
+}
+
+// minPreviewWidth is the narrowest code preview still shown beside the info
+// card; below it the two are stacked.
+const minPreviewWidth = 40
+
+// cardStyle sizes the file info card to the terminal: it shrinks to fit
+// narrow windows, and cards without a code preview beside them use up to
+// half of a wide one.
+func (m model) cardStyle(code bool) lipgloss.Style {
+	style := fileStyle
+	if code {
+		style = codeFileStyle
+	}
+	if m.width == 0 {
+		return style
+	}
+
+	width := style.GetWidth()
+	if !code {
+		width = max(width, m.width/2)
+	}
+	// Leave room for the border
+	return style.Copy().Width(max(min(width, m.width-2), 20))
+}
+
+// stackPreview reports whether the code preview goes below the info card
+// because the window is too narrow to put them side by side.
+func (m model) stackPreview() bool {
+	return m.width > 0 && m.width-m.cardStyle(true).GetWidth()-6 < minPreviewWidth
+}
+
+// progressGauge draws a bar width cells wide, filled in proportion to
+// done/total, followed by the percentage.
+func progressGauge(done, total, width int) string {
+	filled := width
+	percent := 100
+	if total > 0 {
+		filled = min(done*width/total, width)
+		percent = min(done*100/total, 100)
+	}
+	bar := progressStyle.Render(strings.Repeat("█", filled)) +
+		disabledHintStyle.Render(strings.Repeat("░", width-filled))
+	return fmt.Sprintf("%s %3d%%", bar, percent)
+}
+
+func limitLines(text string, n int) string {
+	lines := strings.Split(text, "\n")
+	if n < 1 || len(lines) <= n {
+		return text
+	}
+	return strings.Join(lines[:n], "\n")
+}
+
+// formatCount writes n with thousands separators, e.g. 1,243.
+func formatCount(n int) string {
+	digits := strconv.Itoa(n)
+	var b strings.Builder
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
 func formatSize(bytes int64) string {
 	const unit = 1024
 	if bytes < unit {
@@ -373,67 +1762,39 @@ func formatSize(bytes int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
-func applySyntaxHighlighting(code, path string) string {
-	// Get lexer for the file
-	lexer := lexers.Match(path)
-	if lexer == nil {
-		// Try to get lexer by extension
-		ext := strings.ToLower(filepath.Ext(path))
-		switch ext {
-		case ".go":
-			lexer = lexers.Get("go")
-		case ".js":
-			lexer = lexers.Get("javascript")
-		case ".ts":
-			lexer = lexers.Get("typescript")
-		case ".py":
-			lexer = lexers.Get("python")
-		case ".json":
-			lexer = lexers.Get("json")
-		case ".md":
-			lexer = lexers.Get("markdown")
-		case ".html":
-			lexer = lexers.Get("html")
-		case ".css":
-			lexer = lexers.Get("css")
-		case ".xml":
-			lexer = lexers.Get("xml")
-		case ".yaml", ".yml":
-			lexer = lexers.Get("yaml")
-		case ".sh", ".bash":
-			lexer = lexers.Get("bash")
-		}
-	}
-	
+func applySyntaxHighlighting(code, path, theme string) string {
+	lexer := lexerFor(path)
+
 	// Fallback to plain text if no lexer found
 	if lexer == nil {
 		return code
 	}
-	
-	// Get terminal formatter with 256 colors
-	formatter := formatters.Get("terminal256")
-	if formatter == nil {
+
+	// Get the terminal formatter matching the detected color support
+	formatterName := chromaFormatterName(colorProfile)
+	if formatterName == "" {
 		return code
 	}
-	
-	// Use a dark theme that works well in terminals
-	style := styles.Get("monokai")
-	if style == nil {
-		style = styles.Fallback
+	formatter := formatters.Get(formatterName)
+	if formatter == nil {
+		return code
 	}
-	
+
+	// The theme was validated at startup
+	style := styles.Get(theme)
+
 	// Tokenize the code
 	iterator, err := lexer.Tokenise(nil, code)
 	if err != nil {
 		return code
 	}
-	
+
 	// Format the tokens
 	var buf bytes.Buffer
 	err = formatter.Format(&buf, style, iterator)
 	if err != nil {
 		return code
 	}
-	
+
 	return buf.String()
 }
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lassejlv/dinder/internal/fixture"
+)
+
+func TestDeleteFileFixture(t *testing.T) {
+	root, cleanup, err := fixture.TempDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	items, err := scanDirectory(root, Config{PreviewLines: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]FileItem)
+	for _, item := range items {
+		byName[item.Name] = item
+	}
+
+	m := initialModel(Config{Dir: root, Dirs: []string{root}, Permanent: true})
+	m.files = items
+	for _, name := range []string{"debug.log", "src", "latest.mp4", "scratch.tmp"} {
+		m.toDelete = append(m.toDelete, byName[name])
+	}
+	// Removed by something else after the scan
+	if err := os.Remove(filepath.Join(root, "scratch.tmp")); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, file := range m.toDelete {
+		msg, ok := m.deleteFile(i)().(fileDeletedMsg)
+		if !ok {
+			t.Fatalf("%s: deleteFile returned %T", file.Name, msg)
+		}
+		if msg.failure != nil {
+			t.Fatalf("%s: %v", file.Name, msg.failure.Err)
+		}
+		if _, err := os.Lstat(file.Path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", file.Name)
+		}
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+
+	if m.deletedCount != 3 {
+		t.Errorf("deletedCount = %d, want 3", m.deletedCount)
+	}
+	if len(m.alreadyGone) != 1 || m.alreadyGone[0] != byName["scratch.tmp"].Path {
+		t.Errorf("alreadyGone = %v, want scratch.tmp", m.alreadyGone)
+	}
+	want := byName["debug.log"].Size + byName["src"].Size + byName["latest.mp4"].Size
+	if m.deletedSize != want {
+		t.Errorf("deletedSize = %d, want %d", m.deletedSize, want)
+	}
+	// Deleting a symlink removes only the link
+	if _, err := os.Stat(filepath.Join(root, "video.mp4")); err != nil {
+		t.Errorf("link target was removed: %v", err)
+	}
+}
+
+func TestDryRunReport(t *testing.T) {
+	root, cleanup, err := fixture.TempDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	items, err := scanDirectory(root, Config{PreviewLines: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := initialModel(Config{Dir: root, Dirs: []string{root}, DryRun: true})
+	m.files = items
+	for _, item := range items {
+		if item.Name == "debug.log" || item.Name == "src" {
+			m.toDelete = append(m.toDelete, item)
+		}
+	}
+	m.screen = ScreenProgress
+
+	for i := range m.toDelete {
+		next, _ := m.Update(m.deleteFile(i)())
+		m = next.(model)
+	}
+	for _, file := range m.toDelete {
+		if _, err := os.Lstat(file.Path); err != nil {
+			t.Errorf("dry run touched %s: %v", file.Name, err)
+		}
+		if m.files[m.indexOfFile(file.Path)].Deleted {
+			t.Errorf("%s marked as deleted by a dry run", file.Name)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := m.writeDeletionReport(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report deletionReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range report.Entries {
+		if entry.Status != "would_delete" {
+			t.Errorf("%s: status %q, want would_delete", entry.Path, entry.Status)
+		}
+	}
+	if s := report.Summary; s.Deleted != 0 || s.FreedBytes != 0 || s.WouldDelete != 2 || s.WouldFreeBytes != m.deletedSize {
+		t.Errorf("summary = %+v", s)
+	}
+}
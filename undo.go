@@ -0,0 +1,114 @@
+package main
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// fileState is the part of a FileItem that review decisions change. Files
+// are found again by path, since the watcher can shift indexes around.
+type fileState struct {
+	path     string
+	decided  bool
+	keep     bool
+	skipped  bool
+	move     bool
+	linkBack bool
+}
+
+// undoRecord is one review action: the file that was on screen and the
+// state every file it touched had before.
+type undoRecord struct {
+	current    string
+	secondPass bool
+	files      []fileState
+}
+
+// beginUndo starts the record for a review action. Every file the action
+// changes has to be remembered before it is changed.
+func (m *model) beginUndo() {
+	record := undoRecord{secondPass: m.secondPass}
+	if m.currentFile >= 0 && m.currentFile < len(m.files) {
+		record.current = m.files[m.currentFile].Path
+	}
+	m.undoStack = append(m.undoStack, record)
+	m.redoStack = nil
+}
+
+// remember adds the current state of m.files[i] to the action being
+// recorded.
+func (m *model) remember(i int) {
+	if len(m.undoStack) == 0 {
+		return
+	}
+	top := &m.undoStack[len(m.undoStack)-1]
+	top.files = append(top.files, stateOf(m.files[i]))
+}
+
+func stateOf(file FileItem) fileState {
+	return fileState{
+		path:     file.Path,
+		decided:  file.Decided,
+		keep:     file.Keep,
+		skipped:  file.Skipped,
+		move:     file.Move,
+		linkBack: file.LinkBack,
+	}
+}
+
+// undo reverts the last review action, however many files it touched, and
+// goes back to the file that was on screen when it was taken.
+func (m model) undo() (tea.Model, tea.Cmd) {
+	if len(m.undoStack) == 0 {
+		m.notice = "Nothing to undo"
+		return m, nil
+	}
+	record := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	m.redoStack = append(m.redoStack, m.apply(record))
+	return m, nil
+}
+
+// redo takes an undone action again. Any new decision clears what there is
+// to redo.
+func (m model) redo() (tea.Model, tea.Cmd) {
+	if len(m.redoStack) == 0 {
+		m.notice = "Nothing to redo"
+		return m, nil
+	}
+	record := m.redoStack[len(m.redoStack)-1]
+	m.redoStack = m.redoStack[:len(m.redoStack)-1]
+	m.undoStack = append(m.undoStack, m.apply(record))
+	return m, nil
+}
+
+// apply puts the files in record back into their recorded state and returns
+// the record that reverses it again.
+func (m *model) apply(record undoRecord) undoRecord {
+	inverse := undoRecord{secondPass: m.secondPass}
+	if m.currentFile >= 0 && m.currentFile < len(m.files) {
+		inverse.current = m.files[m.currentFile].Path
+	}
+
+	// A file can be remembered twice, e.g. skipped and then revisited;
+	// restoring in reverse leaves the earliest state
+	for k := len(record.files) - 1; k >= 0; k-- {
+		state := record.files[k]
+		i := m.indexOfFile(state.path)
+		if i < 0 {
+			continue
+		}
+		inverse.files = append(inverse.files, stateOf(m.files[i]))
+		if state.decided {
+			m.decide(i, state.keep)
+		} else {
+			m.undecide(i)
+		}
+		m.files[i].Skipped = state.skipped
+		m.files[i].Move = state.move
+		m.files[i].LinkBack = state.linkBack
+	}
+	m.secondPass = record.secondPass
+	if i := m.indexOfFile(record.current); i >= 0 {
+		m.currentFile = i
+	}
+	m.refreshPreview()
+	return inverse
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+type watcherReadyMsg struct {
+	watcher *fsnotify.Watcher
+	filter  *scanFilter
+}
+type fileAddedMsg FileItem
+type fileChangedMsg FileItem
+type fileRemovedMsg string
+type watchIgnoredMsg struct{}
+
+// watchErrorMsg reports a problem with the watch. It never ends the review;
+// the files already queued can still be decided on.
+type watchErrorMsg struct{ err error }
+
+// startWatching sets up the watch on cfg.Dir, along with the filter new
+// files are checked against, which is loaded once for the whole session.
+func startWatching(cfg Config) tea.Cmd {
+	return func() tea.Msg {
+		filter, err := newScanFilter(cfg.Dir, cfg)
+		if err != nil {
+			return watchErrorMsg{err}
+		}
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return watchErrorMsg{err}
+		}
+		if err := watcher.Add(cfg.Dir); err != nil {
+			watcher.Close()
+			return watchErrorMsg{err}
+		}
+		return watcherReadyMsg{watcher: watcher, filter: filter}
+	}
+}
+
+// waitForChange blocks until the watcher reports something relevant to the
+// review queue and turns it into a message. The model re-issues it after
+// every event so the watch keeps running for the whole session.
+func waitForChange(watcher *fsnotify.Watcher, filter *scanFilter, cfg Config) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			path := filepath.Clean(event.Name)
+			if strings.HasPrefix(filepath.Base(path), ".") && !cfg.IncludeHidden {
+				return watchIgnoredMsg{}
+			}
+
+			switch {
+			case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+				return fileRemovedMsg(path)
+			case event.Has(fsnotify.Create), event.Has(fsnotify.Write):
+				info, err := os.Lstat(path)
+				if err != nil {
+					// Gone again before we could look at it
+					return fileRemovedMsg(path)
+				}
+				if filter.skip(path, fs.FileInfoToDirEntry(info)) {
+					return watchIgnoredMsg{}
+				}
+				item := newFileItem(path, info, cfg)
+				if !matchesFilters(item, cfg) {
+					return watchIgnoredMsg{}
+				}
+				if event.Has(fsnotify.Create) {
+					return fileAddedMsg(item)
+				}
+				return fileChangedMsg(item)
+			}
+			return watchIgnoredMsg{}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return watchErrorMsg{err}
+		}
+	}
+}
+
+func (m model) indexOfFile(path string) int {
+	for i, file := range m.files {
+		if file.Path == path {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m model) handleWatchEvent(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next := waitForChange(m.watcher, m.watchFilter, m.cfg)
+
+	switch msg := msg.(type) {
+	case watchErrorMsg:
+		m.notice = fmt.Sprintf("Watching for new files: %v", msg.err)
+		if m.watcher == nil {
+			// The watch never started, so there is nothing to wait on
+			return m, nil
+		}
+
+	case fileAddedMsg:
+		if m.indexOfFile(msg.Path) < 0 {
+			item := FileItem(msg)
+			m.markSuggestion(&item)
+			m.files = append(m.files, item)
+		}
+
+	case fileChangedMsg:
+		// Downloads grow after creation; refresh anything not yet decided
+		if i := m.indexOfFile(msg.Path); i >= 0 && !m.files[i].Decided {
+			item := FileItem(msg)
+			item.Skipped = m.files[i].Skipped
+			m.markSuggestion(&item)
+			m.files[i] = item
+		}
+
+	case fileRemovedMsg:
+		i := m.indexOfFile(string(msg))
+		if i < 0 || m.screen == ScreenProgress || m.screen == ScreenComplete {
+			break
+		}
+		m.undecide(i)
+		m.files = append(m.files[:i], m.files[i+1:]...)
+		if i < m.currentFile {
+			m.currentFile--
+		}
+		if i < m.zoomReturn {
+			m.zoomReturn--
+		}
+		if i < m.searchReturn {
+			m.searchReturn--
+		}
+		if m.screen == ScreenConfirm {
+			m.prepareConfirmation()
+		} else if m.screen == ScreenReview && i == m.currentFile {
+			// The card under review vanished; move on to the next one
+			m.currentFile--
+			model, cmd := m.nextFile()
+			return model, tea.Batch(cmd, next)
+		}
+	}
+
+	return m, next
+}